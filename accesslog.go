@@ -0,0 +1,250 @@
+package fastroute
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RequestIDHeader is the request and response header
+// carrying the request ID logged by AccessLog.
+const RequestIDHeader = "X-Request-ID"
+
+// LogFormat is the format of AccessLog entries.
+type LogFormat int
+
+// Access log formats. Common and Combined are followed by
+// the quoted route pattern, latency in seconds and request
+// ID, while JSON entries have route parameters too.
+const (
+	CommonLog LogFormat = iota
+	CombinedLog
+	JSONLog
+)
+
+// AccessLog writes an entry for every served request of
+// the routers wrapped by Log, with the method, path, the
+// route pattern and parameters, response status, bytes
+// written, latency and the request ID.
+type AccessLog struct {
+	Out    io.Writer        // os.Stderr if nil
+	Format LogFormat        // CommonLog by default
+	Now    func() time.Time // clock, time.Now if nil
+
+	mu sync.Mutex
+}
+
+// RequestID returns the request ID, which is given by
+// the client or generated by AccessLog.
+func RequestID(req *http.Request) string {
+	return req.Header.Get(RequestIDHeader)
+}
+
+// Log wraps router in order to write access log entries for
+// every served request it routes. The same as Metrics, requests
+// which cannot be routed are passed on as nil handler, only when
+// the logged router serves the request by itself, unmatched
+// request is logged with empty pattern.
+//
+// The request ID is taken from RequestIDHeader, if the client
+// has given a valid one, otherwise it is generated. Either way
+// it is set to the request and response headers.
+func (l *AccessLog) Log(router Router) Router {
+	return &logged{router: router, log: l}
+}
+
+type logged struct {
+	router Router
+	log    *AccessLog
+}
+
+func (l *logged) Route(req *http.Request) http.Handler {
+	h := l.router.Route(req)
+	if h == nil {
+		return nil
+	}
+	var e entry
+	if route := Matched(req); route != nil {
+		e.pattern = route.Pattern
+		e.params = append(e.params, Parameters(req)...) // parameters are recycled when served
+	}
+	return l.observe(h, e)
+}
+
+func (l *logged) unwrap() Router              { return l.router }
+func (l *logged) rewrap(router Router) Router { return l.log.Log(router) }
+func (l *logged) String() string              { return "AccessLog" }
+
+func (l *logged) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h := l.Route(req); h != nil {
+		h.ServeHTTP(w, req)
+	} else {
+		l.observe(http.NotFoundHandler(), entry{}).ServeHTTP(w, req)
+	}
+}
+
+func (l *logged) observe(h http.Handler, e entry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		id := RequestID(req)
+		if !validRequestID(id) {
+			id = newRequestID()
+			req.Header.Set(RequestIDHeader, id)
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		e := e // the entry is completed when served
+		e.req, e.id = req, id
+		e.start = l.log.now()
+		sw := &statusWriter{ResponseWriter: w}
+		h.ServeHTTP(sw, req)
+		e.latency = l.log.now().Sub(e.start)
+		e.status, e.size = sw.Status(), sw.size
+		l.log.write(&e)
+	})
+}
+
+type entry struct {
+	req     *http.Request
+	id      string
+	pattern string
+	params  Params
+	start   time.Time
+	latency time.Duration
+	status  int
+	size    int64
+}
+
+func (l *AccessLog) now() time.Time {
+	if l.Now != nil {
+		return l.Now()
+	}
+	return time.Now()
+}
+
+func (l *AccessLog) write(e *entry) {
+	var buf bytes.Buffer
+	switch l.Format {
+	case JSONLog:
+		e.writeJSON(&buf)
+	default:
+		e.writeCommon(&buf, l.Format == CombinedLog)
+	}
+
+	out := l.Out
+	if out == nil {
+		out = os.Stderr
+	}
+	l.mu.Lock()
+	out.Write(buf.Bytes())
+	l.mu.Unlock()
+}
+
+// writes Common or Combined Log Format line followed by the pattern,
+// latency and request ID
+func (e *entry) writeCommon(buf *bytes.Buffer, combined bool) {
+	req := e.req
+	buf.WriteString(orDash(remoteHost(req)))
+	buf.WriteString(" - ")
+	user, _, _ := req.BasicAuth()
+	buf.WriteString(orDash(escapeLog(user)))
+	buf.WriteString(e.start.Format(" [02/Jan/2006:15:04:05 -0700] \""))
+	uri := req.RequestURI
+	if uri == "" {
+		uri = req.URL.RequestURI() // client request
+	}
+	buf.WriteString(escapeLog(req.Method + " " + uri + " " + req.Proto))
+	buf.WriteString("\" ")
+	buf.WriteString(strconv.Itoa(e.status))
+	buf.WriteByte(' ')
+	if e.size > 0 {
+		buf.WriteString(strconv.FormatInt(e.size, 10))
+	} else {
+		buf.WriteByte('-')
+	}
+	if combined {
+		buf.WriteString(" \"" + escapeLog(req.Referer()) + "\" \"" + escapeLog(req.UserAgent()) + "\"")
+	}
+	buf.WriteString(" \"" + escapeLog(e.pattern) + "\" ")
+	buf.WriteString(strconv.FormatFloat(e.latency.Seconds(), 'f', 6, 64))
+	buf.WriteByte(' ')
+	buf.WriteString(escapeLog(e.id))
+	buf.WriteByte('\n')
+}
+
+func (e *entry) writeJSON(buf *bytes.Buffer) {
+	params := make(map[string]string, len(e.params))
+	for _, p := range e.params {
+		params[p.Key] = p.Value
+	}
+	json.NewEncoder(buf).Encode(struct {
+		Time      string            `json:"time"`
+		RequestID string            `json:"request_id"`
+		Remote    string            `json:"remote"`
+		Method    string            `json:"method"`
+		Path      string            `json:"path"`
+		Pattern   string            `json:"pattern"`
+		Params    map[string]string `json:"params"`
+		Status    int               `json:"status"`
+		Bytes     int64             `json:"bytes"`
+		Latency   float64           `json:"latency"`
+	}{
+		Time:      e.start.Format(time.RFC3339Nano),
+		RequestID: e.id,
+		Remote:    remoteHost(e.req),
+		Method:    e.req.Method,
+		Path:      e.req.URL.Path,
+		Pattern:   e.pattern,
+		Params:    params,
+		Status:    e.status,
+		Bytes:     e.size,
+		Latency:   e.latency.Seconds(),
+	})
+}
+
+func remoteHost(req *http.Request) string {
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		return host
+	}
+	return req.RemoteAddr
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// escapes quotes, backslashes and non printable characters
+func escapeLog(s string) string {
+	q := strconv.Quote(s)
+	return q[1 : len(q)-1]
+}
+
+// client given request ID is accepted if it is short
+// and made only of printable ASCII characters
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,112 @@
+package fastroute_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestAccessLogFormats(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, "user ", fastroute.Parameters(req).ByName("id"), " ", fastroute.RequestID(req))
+	}
+
+	cases := map[fastroute.LogFormat]string{
+		fastroute.CommonLog: `192.0.2.1 - alice [18/Oct/2026:10:00:00 +0000] "GET /users/7?x=\"y\" HTTP/1.1" 200 12 "/users/:id" 0.250000 req-1
+192.0.2.1 - - [18/Oct/2026:10:00:00 +0000] "GET /posts HTTP/1.1" 404 19 "" 0.250000 `,
+		fastroute.CombinedLog: `192.0.2.1 - alice [18/Oct/2026:10:00:00 +0000] "GET /users/7?x=\"y\" HTTP/1.1" 200 12 "http://example.com/" "test/1.0" "/users/:id" 0.250000 req-1
+192.0.2.1 - - [18/Oct/2026:10:00:00 +0000] "GET /posts HTTP/1.1" 404 19 "" "" "" 0.250000 `,
+	}
+	for format, expected := range cases {
+		var out bytes.Buffer
+		now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+		log := &fastroute.AccessLog{Out: &out, Format: format, Now: func() time.Time {
+			now = now.Add(250 * time.Millisecond)
+			return now
+		}}
+		router := log.Log(fastroute.New("/users/:id", handler))
+
+		req := httptest.NewRequest("GET", `/users/7?x="y"`, nil)
+		req.SetBasicAuth("alice", "secret")
+		req.Header.Set("Referer", "http://example.com/")
+		req.Header.Set("User-Agent", "test/1.0")
+		req.Header.Set(fastroute.RequestIDHeader, "req-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Body.String() != "user 7 req-1" || w.Header().Get(fastroute.RequestIDHeader) != "req-1" {
+			t.Fatalf("unexpected response: %q, request ID: %s", w.Body.String(), w.Header().Get(fastroute.RequestIDHeader))
+		}
+
+		req = httptest.NewRequest("GET", "/posts", nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		id := w.Header().Get(fastroute.RequestIDHeader)
+		if len(id) != 32 || fastroute.RequestID(req) != id {
+			t.Fatalf("expected generated request ID, but got: %q", id)
+		}
+
+		// generated request ID is logged at the end of the second line
+		if lines := strings.Replace(out.String(), id, "", 1); lines != expected+"\n" {
+			t.Fatalf("unexpected log for format %d:\n%s\nexpected:\n%s", format, out.String(), expected)
+		}
+	}
+}
+
+func TestAccessLogJSON(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	log := &fastroute.AccessLog{Out: &out, Format: fastroute.JSONLog}
+	router := fastroute.Chain(
+		log.Log(fastroute.New("/repos/:owner/:repo", func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, "created")
+		})),
+		fastroute.New("/", http.NotFoundHandler()),
+	)
+
+	req := httptest.NewRequest("POST", "/repos/DATA-DOG/fastroute", nil)
+	req.Header.Set(fastroute.RequestIDHeader, "bad id")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// not routed by the logged router
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	var entry struct {
+		Time      time.Time         `json:"time"`
+		RequestID string            `json:"request_id"`
+		Remote    string            `json:"remote"`
+		Method    string            `json:"method"`
+		Path      string            `json:"path"`
+		Pattern   string            `json:"pattern"`
+		Params    map[string]string `json:"params"`
+		Status    int               `json:"status"`
+		Bytes     int64             `json:"bytes"`
+		Latency   float64           `json:"latency"`
+	}
+	dec := json.NewDecoder(&out)
+	if err := dec.Decode(&entry); err != nil {
+		t.Fatal(err)
+	}
+	if dec.More() {
+		t.Fatalf("expected single log entry, but got more: %s", out.String())
+	}
+
+	switch {
+	case entry.Time.IsZero(), entry.Latency < 0, len(entry.RequestID) != 32:
+		t.Fatalf("unexpected entry time, latency or request ID: %+v", entry)
+	case entry.Remote != "192.0.2.1" || entry.Method != "POST" || entry.Path != "/repos/DATA-DOG/fastroute":
+		t.Fatalf("unexpected entry request: %+v", entry)
+	case entry.Pattern != "/repos/:owner/:repo" || entry.Params["owner"] != "DATA-DOG" || entry.Params["repo"] != "fastroute":
+		t.Fatalf("unexpected entry route: %+v", entry)
+	case entry.Status != 201 || entry.Bytes != 7:
+		t.Fatalf("unexpected entry response: %+v", entry)
+	}
+}
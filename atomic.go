@@ -0,0 +1,61 @@
+package fastroute
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Atomic is a Router, which may be replaced at any time,
+// for example when routes are rebuilt after configuration
+// change, without any locking while routing requests.
+//
+// Requests already routed are served and recycled by the
+// router which has matched them, the replacement takes
+// effect for the requests routed after Store.
+//
+// The zero value is ready to use and does not route
+// any request until a Router is stored.
+type Atomic struct {
+	v atomic.Value
+}
+
+// atomic.Value requires the same concrete type to be stored
+type atomicRouter struct {
+	Router
+}
+
+// NewAtomic creates Atomic router initialized with r.
+func NewAtomic(r Router) *Atomic {
+	a := &Atomic{}
+	a.Store(r)
+	return a
+}
+
+// Store replaces the current Router with r.
+func (a *Atomic) Store(r Router) {
+	a.v.Store(atomicRouter{r})
+}
+
+// Load returns the current Router or nil.
+func (a *Atomic) Load() Router {
+	r, _ := a.v.Load().(atomicRouter)
+	return r.Router
+}
+
+// Route delegates the request to the current Router.
+func (a *Atomic) Route(req *http.Request) http.Handler {
+	if r := a.Load(); r != nil {
+		return r.Route(req)
+	}
+	return nil
+}
+
+// ServeHTTP routes and serves the request by the current
+// Router, or fallbacks to http.NotFound.
+func (a *Atomic) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h := a.Route(req); h != nil {
+		h.ServeHTTP(w, req)
+	} else {
+		http.NotFound(w, req)
+	}
+}
@@ -0,0 +1,87 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestAtomicRouterSwap(t *testing.T) {
+	t.Parallel()
+	var router fastroute.Atomic
+
+	req, _ := http.NewRequest("GET", "/users/5", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != 404 {
+		t.Fatalf("expected empty atomic router not to match, but got: %d", w.Code)
+	}
+
+	entered, release := make(chan struct{}), make(chan struct{})
+	router.Store(fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		close(entered)
+		<-release
+		fmt.Fprintf(w, "old:%s", fastroute.Parameters(req).ByName("id"))
+	}))
+
+	var wg sync.WaitGroup
+	inflight := httptest.NewRecorder()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		router.ServeHTTP(inflight, req)
+	}()
+
+	<-entered
+	router.Store(fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "new:%s", fastroute.Parameters(req).ByName("id"))
+	}))
+
+	req2, _ := http.NewRequest("GET", "/users/6", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req2)
+	if w.Body.String() != "new:6" {
+		t.Fatalf("expected request to be served by the new router, but got: %s", w.Body.String())
+	}
+
+	close(release)
+	wg.Wait()
+	if inflight.Body.String() != "old:5" {
+		t.Fatalf("expected in-flight request to finish on the old router, but got: %s", inflight.Body.String())
+	}
+
+	if params := fastroute.Parameters(req); params != nil {
+		t.Fatal("parameters should have been recycled")
+	}
+}
+
+func TestAtomicRouterConcurrentSwap(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, fastroute.Parameters(req).ByName("id"))
+	}
+	router := fastroute.NewAtomic(fastroute.New("/users/:id", handler))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			router.Store(fastroute.New("/users/:id", handler))
+		}()
+		go func(i int) {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/users/%d", i), nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Body.String() != fmt.Sprint(i) {
+				t.Errorf("unexpected response: %s", w.Body.String())
+			}
+		}(i)
+	}
+	wg.Wait()
+}
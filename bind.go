@@ -0,0 +1,182 @@
+package fastroute
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Handle creates Router, the same way as New, serving fn with
+// path parameters bound to the fields of struct T by "param"
+// tag. Fields may be strings, numbers, booleans or implement
+// encoding.TextUnmarshaler:
+//
+//	type UserPost struct {
+//		User int    `param:"user"`
+//		Slug string `param:"slug"`
+//	}
+//
+//	fastroute.Handle("/users/:user/posts/:slug", func(w http.ResponseWriter, req *http.Request, p UserPost) {
+//		fmt.Fprintln(w, p.User, p.Slug)
+//	})
+//
+// If T has Validate() error method, it is called after the
+// parameters are decoded. Requests with parameters failing
+// to decode or validate are responded with 400 Bad Request.
+//
+// Handle panics if T is not a struct, any parameter of the
+// pattern has no matching field or a tagged field has no
+// matching parameter or is not of supported type.
+func Handle[T any](pattern string, fn func(http.ResponseWriter, *http.Request, T)) Router {
+	b := binder[T](pattern)
+	return New(pattern, func(w http.ResponseWriter, req *http.Request) {
+		var v T
+		if err := b.bind(reflect.ValueOf(&v).Elem(), Parameters(req)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fn(w, req, v)
+	})
+}
+
+// BindError describes the path parameter, which
+// could not be bound to the field or is not valid.
+type BindError struct {
+	Param string // empty if the validation has failed
+	Err   error
+}
+
+func (e *BindError) Error() string {
+	if e.Param == "" {
+		return "invalid path parameters: " + e.Err.Error()
+	}
+	return "invalid path parameter " + e.Param + ": " + e.Err.Error()
+}
+
+// Unwrap returns the decoding or validation error.
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+type binding struct {
+	param string
+	field int
+	set   func(reflect.Value, string) error
+}
+
+type bindings []binding
+
+var (
+	unmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	validatorType   = reflect.TypeOf((*interface{ Validate() error })(nil)).Elem()
+)
+
+// resolves field bindings of T for every pattern parameter
+func binder[T any](pattern string) bindings {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("handler parameters must be a struct, but got: %s", t))
+	}
+
+	params := make(map[string]bool)
+	for _, seg := range strings.Split(pattern, "/") {
+		if len(seg) > 1 && (seg[0] == ':' || seg[0] == '*') {
+			params[seg[1:]] = false
+		}
+	}
+
+	var b bindings
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, ok := f.Tag.Lookup("param")
+		if !ok {
+			continue
+		}
+		if _, ok := params[name]; !ok {
+			panic(fmt.Sprintf("field %s.%s is bound to param %s, which is not in pattern: %s", t, f.Name, name, pattern))
+		}
+		set := setter(f.Type)
+		if set == nil || !f.IsExported() {
+			panic(fmt.Sprintf("field %s.%s cannot be bound to param: %s", t, f.Name, name))
+		}
+		params[name] = true
+		b = append(b, binding{param: name, field: i, set: set})
+	}
+
+	for name, bound := range params {
+		if !bound {
+			panic(fmt.Sprintf("param %s of pattern %s has no matching field in: %s", name, pattern, t))
+		}
+	}
+	return b
+}
+
+// decodes parameters into struct v and validates it
+func (b bindings) bind(v reflect.Value, ps Params) error {
+	for _, f := range b {
+		if err := f.set(v.Field(f.field), ps.ByName(f.param)); err != nil {
+			return &BindError{Param: f.param, Err: err}
+		}
+	}
+	if v.Addr().Type().Implements(validatorType) {
+		if err := v.Addr().Interface().(interface{ Validate() error }).Validate(); err != nil {
+			return &BindError{Err: err}
+		}
+	}
+	return nil
+}
+
+// returns function decoding parameter into the field of type t,
+// nil if the type is not supported
+func setter(t reflect.Type) func(reflect.Value, string) error {
+	if reflect.PointerTo(t).Implements(unmarshalerType) {
+		return func(f reflect.Value, s string) error {
+			return f.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return func(f reflect.Value, s string) error {
+			f.SetString(s)
+			return nil
+		}
+	case reflect.Bool:
+		return func(f reflect.Value, s string) error {
+			v, err := strconv.ParseBool(s)
+			f.SetBool(v)
+			return unwrapNum(err)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(f reflect.Value, s string) error {
+			v, err := strconv.ParseInt(s, 10, t.Bits())
+			f.SetInt(v)
+			return unwrapNum(err)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(f reflect.Value, s string) error {
+			v, err := strconv.ParseUint(s, 10, t.Bits())
+			f.SetUint(v)
+			return unwrapNum(err)
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(f reflect.Value, s string) error {
+			v, err := strconv.ParseFloat(s, t.Bits())
+			f.SetFloat(v)
+			return unwrapNum(err)
+		}
+	}
+	return nil
+}
+
+// strips the function and input from strconv errors,
+// since the parameter name is reported instead
+func unwrapNum(err error) error {
+	if e, ok := err.(*strconv.NumError); ok {
+		return e.Err
+	}
+	return err
+}
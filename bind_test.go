@@ -0,0 +1,105 @@
+package fastroute_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+type postParams struct {
+	User  uint8     `param:"user"`
+	Slug  string    `param:"slug"`
+	Draft bool      `param:"draft"`
+	Score float64   `param:"score"`
+	Date  time.Time `param:"date"`
+	Path  string    `param:"path"`
+}
+
+func (p *postParams) Validate() error {
+	if p.Slug == "new" {
+		return errors.New("slug is reserved")
+	}
+	return nil
+}
+
+func TestHandleBindsParams(t *testing.T) {
+	t.Parallel()
+	router := fastroute.Handle("/users/:user/posts/:slug/:draft/:score/:date/*path", func(w http.ResponseWriter, req *http.Request, p postParams) {
+		fmt.Fprintf(w, "%d %s %t %g %s %s", p.User, p.Slug, p.Draft, p.Score, p.Date.Format("2006-01-02"), p.Path)
+	})
+
+	cases := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/users/5/posts/go/true/1.5/2020-01-02T00:00:00Z/a/b", 200, "5 go true 1.5 2020-01-02 /a/b"},
+		{"/users/300/posts/go/true/1.5/2020-01-02T00:00:00Z/", 400, "invalid path parameter user: value out of range\n"},
+		{"/users/x/posts/go/true/1.5/2020-01-02T00:00:00Z/", 400, "invalid path parameter user: invalid syntax\n"},
+		{"/users/5/posts/go/maybe/1.5/2020-01-02T00:00:00Z/", 400, "invalid path parameter draft: invalid syntax\n"},
+		{"/users/5/posts/go/true/1.5/yesterday/", 400, "invalid path parameter date: " + timeError("yesterday") + "\n"},
+		{"/users/5/posts/new/true/1.5/2020-01-02T00:00:00Z/", 400, "invalid path parameters: slug is reserved\n"},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest("GET", c.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != c.code || w.Body.String() != c.body {
+			t.Fatalf("unexpected response for %s: %d %q", c.path, w.Code, w.Body.String())
+		}
+		if fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters to be recycled for: %s", c.path)
+		}
+	}
+}
+
+func timeError(s string) string {
+	var v time.Time
+	return v.UnmarshalText([]byte(s)).Error()
+}
+
+func TestHandleRegistrationChecks(t *testing.T) {
+	t.Parallel()
+	type id struct {
+		ID int `param:"id"`
+	}
+	type unsupported struct {
+		ID []int `param:"id"`
+	}
+	type unexported struct {
+		id int `param:"id"`
+	}
+	cases := map[string]func(){
+		"must be a struct, but got: string": func() {
+			fastroute.Handle("/users/:id", func(http.ResponseWriter, *http.Request, string) {})
+		},
+		"param name of pattern /users/:id/:name has no matching field": func() {
+			fastroute.Handle("/users/:id/:name", func(http.ResponseWriter, *http.Request, id) {})
+		},
+		"bound to param id, which is not in pattern: /users/:name": func() {
+			fastroute.Handle("/users/:name", func(http.ResponseWriter, *http.Request, id) {})
+		},
+		"field fastroute_test.unsupported.ID cannot be bound to param: id": func() {
+			fastroute.Handle("/users/:id", func(http.ResponseWriter, *http.Request, unsupported) {})
+		},
+		"field fastroute_test.unexported.id cannot be bound to param: id": func() {
+			fastroute.Handle("/users/:id", func(http.ResponseWriter, *http.Request, unexported) {})
+		},
+	}
+	for expected, register := range cases {
+		func() {
+			defer func() {
+				if err := recover(); err == nil || !strings.Contains(fmt.Sprint(err), expected) {
+					t.Fatalf("expected panic containing %q, but got: %v", expected, err)
+				}
+			}()
+			register()
+		}()
+	}
+}
@@ -0,0 +1,103 @@
+package fastroute
+
+import (
+	"net/http"
+	"time"
+)
+
+// Bounds limit the time to serve the request
+// and the request body size. Zero values mean
+// unlimited.
+type Bounds struct {
+	Timeout     time.Duration
+	MaxBodySize int64 // in bytes
+}
+
+// Timeout and MaxBodySize metadata keys override
+// Bound defaults for the route they are attached to:
+//
+//	fastroute.With(fastroute.New("/upload/:id", upload),
+//		fastroute.Timeout.Value(30*time.Second),
+//		fastroute.MaxBodySize.Value(100<<20),
+//	)
+var (
+	Timeout     = NewKey[time.Duration]("timeout")
+	MaxBodySize = NewKey[int64]("maxbodysize")
+)
+
+// Bound wraps router in order to limit serving time and
+// request body size of every request it routes, by the
+// bounds of the route matched or defaults.
+//
+// Requests which exceed the timeout are responded with 503
+// Service Unavailable, see http.TimeoutHandler. Reading the
+// body over the limit fails, see http.MaxBytesReader. The
+// body is limited in place, so the route parameters remain
+// bound to the request and are recycled as usual.
+//
+// Since timed out routes keep running in their own goroutine,
+// the Match of a tracked request is recorded before the route
+// is served, see Track.
+func Bound(router Router, defaults Bounds) Router {
+	return &bounded{router: router, defaults: defaults}
+}
+
+type bounded struct {
+	router   Router
+	defaults Bounds
+}
+
+func (b *bounded) Route(req *http.Request) http.Handler {
+	h := b.router.Route(req)
+	if h == nil {
+		return nil
+	}
+
+	bounds := b.defaults
+	if route := Matched(req); route != nil {
+		if timeout, ok := Timeout.Of(route); ok {
+			bounds.Timeout = timeout
+		}
+		if size, ok := MaxBodySize.Of(route); ok {
+			bounds.MaxBodySize = size
+		}
+	}
+	if bounds.Timeout > 0 {
+		h = http.TimeoutHandler(h, bounds.Timeout, http.StatusText(http.StatusServiceUnavailable))
+	}
+	if bounds.MaxBodySize <= 0 && bounds.Timeout <= 0 {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body := req.Body
+		if p, _ := req.Body.(*parameters); p != nil {
+			body = p.body
+			if bounds.MaxBodySize > 0 && p.ReadCloser != nil {
+				p.ReadCloser = http.MaxBytesReader(w, p.ReadCloser, bounds.MaxBodySize)
+			}
+		} else if bounds.MaxBodySize > 0 && req.Body != nil {
+			req.Body = http.MaxBytesReader(w, req.Body, bounds.MaxBodySize)
+		}
+		if bounds.Timeout > 0 {
+			// timeout handler serves the route in another goroutine
+			h.ServeHTTP(w, trackHandover(req))
+		} else {
+			h.ServeHTTP(w, req)
+		}
+		// timeout handler serves a request copy, which is recycled instead
+		req.Body = body
+	})
+}
+
+func (b *bounded) unwrap() Router              { return b.router }
+func (b *bounded) rewrap(router Router) Router { return Bound(router, b.defaults) }
+func (b *bounded) String() string              { return "Bound" }
+
+func (b *bounded) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h := b.Route(req); h != nil {
+		h.ServeHTTP(w, req)
+	} else {
+		http.NotFound(w, req)
+	}
+}
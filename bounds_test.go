@@ -0,0 +1,84 @@
+package fastroute_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestBoundBodySizeAndTimeout(t *testing.T) {
+	t.Parallel()
+	read := func(w http.ResponseWriter, req *http.Request) {
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.Write([]byte(fastroute.Parameters(req).ByName("id") + ":" + string(data)))
+	}
+	release := make(chan struct{})
+	defer close(release)
+
+	router := fastroute.Bound(fastroute.Chain(
+		fastroute.New("/small/:id", read),
+		fastroute.With(fastroute.New("/upload/:id", read), fastroute.MaxBodySize.Value(int64(10))),
+		fastroute.With(fastroute.New("/slow/:id", func(w http.ResponseWriter, req *http.Request) {
+			select {
+			case <-release:
+			case <-req.Context().Done():
+			}
+		}), fastroute.Timeout.Value(time.Millisecond)),
+	), fastroute.Bounds{Timeout: time.Minute, MaxBodySize: 4})
+
+	cases := []struct {
+		path, body string
+		code       int
+		response   string
+	}{
+		{"/small/1", "abcd", 200, "1:abcd"},
+		{"/small/1", "abcde", 413, "http: request body too large\n"},
+		{"/upload/2", "abcdefghij", 200, "2:abcdefghij"},
+		{"/slow/3", "", 503, "Service Unavailable"},
+	}
+
+	for _, c := range cases {
+		body := io.NopCloser(strings.NewReader(c.body))
+		req, _ := http.NewRequest("POST", c.path, body)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != c.code || w.Body.String() != c.response {
+			t.Fatalf("unexpected response for %s: %d %q", c.path, w.Code, w.Body.String())
+		}
+		if fastroute.Matched(req) != nil || req.Body != body {
+			t.Fatalf("expected original request body to be restored for: %s", c.path)
+		}
+	}
+}
+
+func TestBoundTimeoutTracksMatchBeforeHandover(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	defer close(release)
+
+	router := fastroute.Bound(fastroute.New("/slow/:id", func(w http.ResponseWriter, req *http.Request) {
+		<-release
+	}), fastroute.Bounds{Timeout: time.Millisecond})
+
+	req, _ := http.NewRequest("GET", "/slow/5", nil)
+	req, m := fastroute.Track(req)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// the route handler is still running, reading the match must not race with it
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected timeout, but got: %d", w.Code)
+	}
+	if m.Route == nil || m.Route.Pattern != "/slow/:id" || m.Params.ByName("id") != "5" {
+		t.Fatalf("unexpected tracked match: %+v", m)
+	}
+}
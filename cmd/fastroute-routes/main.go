@@ -0,0 +1,83 @@
+// Command fastroute-routes prints routes declared in a route
+// table or OpenAPI 3 JSON document, as an indented tree, table
+// or Graphviz DOT digraph, warning about shadowed routes.
+//
+// Usage:
+//
+//	fastroute-routes [-format tree|table|dot] [-openapi] file.json
+//
+// For example, render the routes as an image:
+//
+//	fastroute-routes -format dot routes.json | dot -Tpng > routes.png
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func main() {
+	format := flag.String("format", "tree", "output format: tree, table or dot")
+	openapi := flag.Bool("openapi", false, "read OpenAPI 3 JSON document instead of route table")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] file.json\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(os.Stdout, flag.Arg(0), *format, *openapi); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(w io.Writer, file, format string, openapi bool) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var router fastroute.Router
+	if openapi {
+		router, err = fastroute.ImportOpenAPI(bytes.NewReader(data), nil)
+	} else {
+		router, err = loadTable(data)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	switch format {
+	case "tree":
+		return fastroute.Dump(w, router)
+	case "table":
+		return fastroute.DumpTable(w, router)
+	case "dot":
+		return fastroute.DumpDOT(w, router)
+	}
+	return fmt.Errorf("unknown format: %s", format)
+}
+
+// loads route table, binding every handler key to a placeholder
+func loadTable(data []byte) (fastroute.Router, error) {
+	var table fastroute.Table
+	handlers := make(map[string]http.Handler)
+	if err := json.Unmarshal(data, &table); err == nil {
+		for _, route := range table.Routes {
+			handlers[route.Handler] = http.NotFoundHandler()
+		}
+	}
+	return fastroute.Load(bytes.NewReader(data), handlers)
+}
@@ -0,0 +1,198 @@
+package fastroute
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSPolicy describes which cross origin requests
+// are allowed for a route.
+type CORSPolicy struct {
+	Origins        []string // allowed origins, "*" allows any origin
+	Headers        []string // allowed request headers, "*" allows any requested
+	ExposedHeaders []string // response headers exposed to the client
+	Credentials    bool     // whether credentials are allowed
+	MaxAge         time.Duration
+}
+
+// CORSPolicies metadata key overrides CORS default
+// policy for the route it is attached to.
+var CORSPolicies = NewKey[CORSPolicy]("cors")
+
+// CORS wraps method routes in order to handle cross origin
+// resource sharing. Preflight requests are answered using the
+// methods which actually have a route for the requested path,
+// and the policy of the route for the requested method. Routes
+// are only probed, so parameters do not leak. Actual requests
+// are served by the matched route with CORS headers set,
+// if the origin is allowed by the route policy. Routed responses
+// always vary by Origin, since they may be cached. Only methods
+// whose route policy allows the origin are advertised.
+//
+// CORS panics, if the default or any route policy allows
+// any origin with credentials, since browsers reject such
+// responses and echoing the origin back would allow
+// credentials for every site.
+func CORS(routes Methods, defaults CORSPolicy) Router {
+	defaults.validate("default")
+	for method, router := range routes {
+		describe(method, router).visit(func(n *node) {
+			if policy, ok := CORSPolicies.Of(n.route); ok {
+				policy.validate(method + " " + n.route.Pattern)
+			}
+		})
+	}
+	return &cors{routes: routes, defaults: defaults}
+}
+
+type cors struct {
+	routes   Methods
+	defaults CORSPolicy
+}
+
+func (c *cors) Route(req *http.Request) http.Handler {
+	origin := req.Header.Get("Origin")
+	requested := req.Header.Get("Access-Control-Request-Method")
+	if req.Method == http.MethodOptions && origin != "" && requested != "" {
+		return c.preflight(req, origin, requested)
+	}
+
+	h := c.routes.Route(req)
+	if h == nil {
+		return nil
+	}
+
+	policy := c.policy(Matched(req))
+	allowed := origin != "" && policy.allows(origin)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// response depends on origin, even if it is not allowed or given,
+		// so that shared caches do not serve it for other origins
+		w.Header().Add("Vary", "Origin")
+		if allowed {
+			policy.setOrigin(w.Header(), origin)
+			if len(policy.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(policy.ExposedHeaders, ", "))
+			}
+		}
+		h.ServeHTTP(w, req)
+	})
+}
+
+func (c *cors) preflight(req *http.Request, origin, requested string) http.Handler {
+	var allows []string // methods allowed for the origin by their route policies
+	var policy CORSPolicy
+	found, allowed := false, false
+	for method, router := range c.routes {
+		if router.Route(req) == nil {
+			continue
+		}
+		p := c.policy(Matched(req))
+		Recycle(req) // we will not serve it, need to recycle
+		found = true
+		if !p.allows(origin) {
+			continue
+		}
+		allows = append(allows, method)
+		if method == requested {
+			policy, allowed = p, true
+		}
+	}
+	if !found {
+		return nil // path is not known
+	}
+	sort.Strings(allows)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		header := w.Header()
+		header.Add("Vary", "Origin")
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+		if allowed {
+			policy.setOrigin(header, origin)
+			header.Set("Access-Control-Allow-Methods", strings.Join(allows, ", "))
+			if headers := policy.allowedHeaders(req.Header.Get("Access-Control-Request-Headers")); headers != "" {
+				header.Set("Access-Control-Allow-Headers", headers)
+			}
+			if policy.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(policy.MaxAge/time.Second)))
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (c *cors) policy(route *Route) CORSPolicy {
+	if policy, ok := CORSPolicies.Of(route); ok {
+		return policy
+	}
+	return c.defaults
+}
+
+func (c *cors) unwrap() Router { return c.routes }
+func (c *cors) String() string { return "CORS" }
+
+func (c *cors) rewrap(router Router) Router {
+	return CORS(router.(Methods), c.defaults) // With keeps Methods
+}
+
+func (c *cors) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h := c.Route(req); h != nil {
+		h.ServeHTTP(w, req)
+	} else {
+		http.NotFound(w, req)
+	}
+}
+
+func (p CORSPolicy) allows(origin string) bool {
+	for _, o := range p.Origins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p CORSPolicy) validate(of string) {
+	for _, o := range p.Origins {
+		if o == "*" && p.Credentials {
+			panic(fmt.Sprintf("%s CORS policy cannot allow any origin with credentials", of))
+		}
+	}
+}
+
+func (p CORSPolicy) setOrigin(header http.Header, origin string) {
+	if p.Credentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+		header.Set("Access-Control-Allow-Origin", origin)
+		return
+	}
+	for _, o := range p.Origins {
+		if o == "*" {
+			header.Set("Access-Control-Allow-Origin", "*")
+			return
+		}
+	}
+	header.Set("Access-Control-Allow-Origin", origin)
+}
+
+// resolves allowed headers of requested ones
+func (p CORSPolicy) allowedHeaders(requested string) string {
+	if requested == "" {
+		return ""
+	}
+	var allowed []string
+	for _, h := range strings.Split(requested, ",") {
+		h = strings.TrimSpace(h)
+		for _, a := range p.Headers {
+			if a == "*" || strings.EqualFold(a, h) {
+				allowed = append(allowed, h)
+				break
+			}
+		}
+	}
+	return strings.Join(allowed, ", ")
+}
@@ -0,0 +1,127 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestCORSPreflightAndActualRequests(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(fastroute.Parameters(req).ByName("id")))
+	}
+	router := fastroute.CORS(fastroute.Methods{
+		"GET": fastroute.New("/users/:id", handler),
+		"PUT": fastroute.New("/users/:id", handler),
+		"DELETE": fastroute.With(fastroute.New("/users/:id", handler), fastroute.CORSPolicies.Value(fastroute.CORSPolicy{
+			Origins:     []string{"https://admin.example.com"},
+			Credentials: true,
+		})),
+	}, fastroute.CORSPolicy{
+		Origins: []string{"*"},
+		Headers: []string{"Content-Type"},
+		MaxAge:  time.Hour,
+	})
+
+	cases := []struct {
+		method, path, origin, requested string
+		code                            int
+		headers                         map[string]string
+	}{
+		{"OPTIONS", "/users/1", "https://app.example.com", "PUT", 204, map[string]string{
+			"Access-Control-Allow-Origin":  "*",
+			"Access-Control-Allow-Methods": "GET, PUT",
+			"Access-Control-Allow-Headers": "content-type",
+			"Access-Control-Max-Age":       "3600",
+		}},
+		{"OPTIONS", "/users/1", "https://app.example.com", "DELETE", 204, map[string]string{
+			"Access-Control-Allow-Origin":  "",
+			"Access-Control-Allow-Methods": "",
+		}},
+		{"OPTIONS", "/users/1", "https://admin.example.com", "DELETE", 204, map[string]string{
+			"Access-Control-Allow-Origin":      "https://admin.example.com",
+			"Access-Control-Allow-Credentials": "true",
+			"Access-Control-Allow-Methods":     "DELETE, GET, PUT",
+			"Access-Control-Allow-Headers":     "",
+		}},
+		{"OPTIONS", "/unknown", "https://app.example.com", "GET", 404, map[string]string{
+			"Access-Control-Allow-Origin": "",
+		}},
+		{"GET", "/users/5", "https://app.example.com", "", 200, map[string]string{
+			"Access-Control-Allow-Origin": "*",
+		}},
+		{"DELETE", "/users/5", "https://app.example.com", "", 200, map[string]string{
+			"Access-Control-Allow-Origin": "",
+			"Vary":                        "Origin",
+		}},
+		{"GET", "/users/5", "", "", 200, map[string]string{
+			"Access-Control-Allow-Origin": "",
+			"Vary":                        "Origin",
+		}},
+		{"DELETE", "/users/5", "https://admin.example.com", "", 200, map[string]string{
+			"Access-Control-Allow-Origin":      "https://admin.example.com",
+			"Access-Control-Allow-Credentials": "true",
+			"Vary":                             "Origin",
+		}},
+	}
+
+	for i, c := range cases {
+		req, _ := http.NewRequest(c.method, c.path, nil)
+		if c.origin != "" {
+			req.Header.Set("Origin", c.origin)
+		}
+		if c.requested != "" {
+			req.Header.Set("Access-Control-Request-Method", c.requested)
+			req.Header.Set("Access-Control-Request-Headers", "content-type, x-custom")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != c.code {
+			t.Fatalf("unexpected response code: %d, case: %d", w.Code, i)
+		}
+		for key, val := range c.headers {
+			if act := w.Header().Get(key); act != val {
+				t.Fatalf("unexpected header %s: %q, expected: %q, case: %d", key, act, val, i)
+			}
+		}
+		if fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters not to leak, case: %d", i)
+		}
+	}
+}
+
+func TestCORSRejectsAnyOriginWithCredentials(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	any := fastroute.CORSPolicy{Origins: []string{"*"}, Credentials: true}
+
+	cases := map[string]func(){
+		"default": func() {
+			fastroute.CORS(fastroute.Methods{"GET": fastroute.New("/users", handler)}, any)
+		},
+		"route": func() {
+			fastroute.CORS(fastroute.Methods{
+				"GET": fastroute.Chain(
+					fastroute.New("/users", handler),
+					fastroute.With(fastroute.New("/users/:id", handler), fastroute.CORSPolicies.Value(any)),
+				),
+			}, fastroute.CORSPolicy{Origins: []string{"*"}})
+		},
+	}
+
+	for name, construct := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected %s policy to be rejected", name)
+				}
+			}()
+			construct()
+		}()
+	}
+}
@@ -0,0 +1,209 @@
+package fastroute
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// describes composition of the router for dumps
+type node struct {
+	label    string
+	method   string
+	route    *Route
+	shadowed *Route // route matched before, making this one unreachable
+	children []*node
+	scoped   bool // children are alternatives, like versions, shadowing only within
+}
+
+func describe(method string, router Router) *node {
+	n := &node{method: method, label: fmt.Sprintf("%T", router)}
+	switch r := router.(type) {
+	case *route:
+		n.route = r.info
+		n.label = r.info.Pattern
+		if r.info.Name != "" {
+			n.label += " (" + r.info.Name + ")"
+		}
+	case chain:
+		n.label = "Chain"
+		for _, sub := range r {
+			n.children = append(n.children, describe(method, sub))
+		}
+	case Methods:
+		n.label = "Methods"
+		methods := make([]string, 0, len(r))
+		for m := range r {
+			methods = append(methods, m)
+		}
+		sort.Strings(methods)
+		for _, m := range methods {
+			child := describe(m, r[m])
+			child.label = m + " " + child.label
+			n.children = append(n.children, child)
+		}
+	case *Atomic:
+		n.label = "Atomic"
+		if current := r.Load(); current != nil {
+			n.children = append(n.children, describe(method, current))
+		}
+	case *versioned:
+		n.label = "Versioned"
+		n.scoped = true
+		for _, version := range r.Versions {
+			child := describe(method, version.Router)
+			child.label = version.Name + " " + child.label
+			n.children = append(n.children, child)
+		}
+	case wrapper:
+		n.label = r.String()
+		n.children = append(n.children, describe(method, r.unwrap()))
+	}
+	return n
+}
+
+// marks routes shadowed by the routes matched before for the same method
+func (n *node) detectShadowed() {
+	n.shadowing(make(map[string][]*Route))
+}
+
+func (n *node) shadowing(seen map[string][]*Route) {
+	if n.route != nil {
+		for _, before := range seen[n.method] {
+			if shadows(before.Pattern, n.route.Pattern) {
+				n.shadowed = before
+				break
+			}
+		}
+		seen[n.method] = append(seen[n.method], n.route)
+	}
+
+	for _, child := range n.children {
+		if !n.scoped {
+			child.shadowing(seen)
+			continue
+		}
+		// every alternative is shadowed by the routes before, but not by each other
+		scope := make(map[string][]*Route, len(seen))
+		for method, routes := range seen {
+			scope[method] = routes[:len(routes):len(routes)]
+		}
+		child.shadowing(scope)
+	}
+}
+
+func (n *node) visit(fn func(*node)) {
+	fn(n)
+	for _, child := range n.children {
+		child.visit(fn)
+	}
+}
+
+// whether every path matched by pattern b is also matched by pattern a
+func shadows(a, b string) bool {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	for i, seg := range as {
+		if seg != "" && seg[0] == '*' {
+			return i < len(bs)
+		}
+		if i >= len(bs) {
+			return false
+		}
+		switch {
+		case seg != "" && seg[0] == ':':
+			if bs[i] == "" || bs[i][0] == '*' {
+				return false
+			}
+		case seg != bs[i]:
+			return false
+		}
+	}
+	return len(as) == len(bs)
+}
+
+// Dump writes the router composition as an indented tree,
+// together with route patterns, names and warnings about
+// routes which are shadowed by the routes matched before.
+//
+//	Methods
+//	  GET Chain
+//	    /users/:id (user)
+//	    /users/:uid  # shadowed by /users/:id
+//	  POST /users
+func Dump(w io.Writer, router Router) error {
+	root := describe("", router)
+	root.detectShadowed()
+
+	bw := bufio.NewWriter(w)
+	var write func(n *node, depth int)
+	write = func(n *node, depth int) {
+		bw.WriteString(strings.Repeat("  ", depth) + n.label)
+		if n.shadowed != nil {
+			bw.WriteString("  # shadowed by " + n.shadowed.Pattern)
+		}
+		bw.WriteByte('\n')
+		for _, child := range n.children {
+			write(child, depth+1)
+		}
+	}
+	write(root, 0)
+	return bw.Flush()
+}
+
+// DumpTable writes routes as a table of methods,
+// patterns, names and shadowing warnings.
+func DumpTable(w io.Writer, router Router) error {
+	root := describe("", router)
+	root.detectShadowed()
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tPATTERN\tNAME\tWARNING")
+	root.visit(func(n *node) {
+		if n.route == nil {
+			return
+		}
+		method, warning := n.method, ""
+		if method == "" {
+			method = "*"
+		}
+		if n.shadowed != nil {
+			warning = "shadowed by " + n.shadowed.Pattern
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", method, n.route.Pattern, n.route.Name, warning)
+	})
+	return tw.Flush()
+}
+
+// DumpDOT writes the router composition as Graphviz DOT
+// digraph, shadowed routes are drawn in red.
+func DumpDOT(w io.Writer, router Router) error {
+	root := describe("", router)
+	root.detectShadowed()
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("digraph routes {\n\trankdir=LR;\n\tnode [shape=box];\n")
+	var id int
+	var write func(n *node) int
+	write = func(n *node) int {
+		id++
+		self := id
+		attrs := fmt.Sprintf("label=%q", n.label)
+		switch {
+		case n.shadowed != nil:
+			attrs += ", color=red, tooltip=" + fmt.Sprintf("%q", "shadowed by "+n.shadowed.Pattern)
+		case n.route == nil:
+			attrs += ", style=rounded"
+		}
+		fmt.Fprintf(bw, "\tn%d [%s];\n", self, attrs)
+		for _, child := range n.children {
+			fmt.Fprintf(bw, "\tn%d -> n%d;\n", self, write(child))
+		}
+		return self
+	}
+	write(root)
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
@@ -0,0 +1,113 @@
+package fastroute_test
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func dumpRouter() fastroute.Router {
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	return fastroute.Methods{
+		"GET": fastroute.Chain(
+			fastroute.Named("user", "/users/:id", handler),
+			fastroute.New("/users/:uid", handler),
+			fastroute.New("/users/me", handler),
+			fastroute.New("/users/me/", handler),
+			fastroute.New("/files/*path", handler),
+			fastroute.New("/files/", handler),
+		),
+		"POST": fastroute.New("/users", handler),
+	}
+}
+
+func TestDumpTree(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := fastroute.Dump(&buf, dumpRouter()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"Methods",
+		"  GET Chain",
+		"    /users/:id (user)",
+		"    /users/:uid  # shadowed by /users/:id",
+		"    /users/me  # shadowed by /users/:id",
+		"    /users/me/",
+		"    /files/*path",
+		"    /files/  # shadowed by /files/*path",
+		"  POST /users",
+		"",
+	}, "\n")
+	if buf.String() != expected {
+		t.Fatalf("unexpected dump:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestDumpTableAndDOT(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := fastroute.DumpTable(&buf, dumpRouter()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "GET     /users/:uid    ") || !strings.Contains(buf.String(), "shadowed by /users/:id\n") {
+		t.Fatalf("unexpected table:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := fastroute.DumpDOT(&buf, dumpRouter()); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"digraph routes {",
+		`n1 [label="Methods", style=rounded];`,
+		`n4 [label="/users/:uid", color=red, tooltip="shadowed by /users/:id"];`,
+		"n1 -> n2;",
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Fatalf("expected dot to contain: %s, but got:\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestDumpVersionsShadowOnlyWithin(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	router := fastroute.Chain(
+		fastroute.New("/status", handler),
+		fastroute.Versioned(fastroute.Versioning{Versions: []fastroute.Version{
+			{Name: "v1", Router: fastroute.Chain(
+				fastroute.New("/users/:id", handler),
+				fastroute.New("/status", handler),
+			)},
+			{Name: "v2", Router: fastroute.Chain(
+				fastroute.New("/users/:id", handler),
+				fastroute.New("/users/:uid", handler),
+			)},
+		}}),
+	)
+
+	var buf bytes.Buffer
+	if err := fastroute.DumpTable(&buf, router); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"METHOD PATTERN NAME WARNING",
+		"* /status",
+		"* /users/:id",
+		"* /status shadowed by /status",
+		"* /users/:id",
+		"* /users/:uid shadowed by /users/:id",
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	for i := range lines {
+		lines[i] = strings.Join(strings.Fields(lines[i]), " ") // ignore alignment
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected table:\n%s", buf.String())
+	}
+}
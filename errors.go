@@ -0,0 +1,80 @@
+package fastroute
+
+import (
+	"errors"
+	"log"
+	"net/http"
+)
+
+// Pattern validation errors, which are wrapped by PatternError
+// to explain why the path pattern cannot be compiled.
+var (
+	ErrParamPosition    = errors.New("special param matching signs, must follow after slash")
+	ErrParamUnnamed     = errors.New("param must be named after sign")
+	ErrCatchAllPosition = errors.New("match all, must be the last segment in pattern")
+	ErrParamsPerSegment = errors.New("only one param per segment")
+)
+
+// PatternError records an invalid path pattern given
+// to Compile, the offending segment index and the reason.
+//
+// Segment is a zero based index of the path segment
+// in the pattern, not counting the leading slash.
+type PatternError struct {
+	Pattern string
+	Segment int
+	Err     error
+}
+
+func (e *PatternError) Error() string {
+	return e.Err.Error() + ": " + e.Pattern
+}
+
+// Unwrap returns the reason, so it can be
+// compared using errors.Is.
+func (e *PatternError) Unwrap() error {
+	return e.Err
+}
+
+// Errors returned by handlers adapted by JSON or Resolver
+// loaders, which are mapped to the response status code by
+// StatusOf. They may be wrapped to give more details.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrInvalidParam = errors.New("invalid path parameter")
+	ErrValidation   = errors.New("validation failed")
+	ErrConflict     = errors.New("conflict")
+)
+
+// StatusOf maps the error to the response status code:
+//
+//	ErrNotFound                  404 Not Found
+//	ErrInvalidParam, *BindError  400 Bad Request
+//	ErrValidation                422 Unprocessable Entity
+//	ErrConflict                  409 Conflict
+//	*Problem                     Problem.Status
+//
+// Other errors are 500 Internal Server Error.
+func StatusOf(err error) int {
+	var problem *Problem
+	var bind *BindError
+	switch {
+	case errors.As(err, &problem) && problem.Status != 0:
+		return problem.Status
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrInvalidParam), errors.As(err, &bind):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrConflict):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// logs the error by the standard logger along with the
+// matched route pattern, when no error log is configured
+func logError(req *http.Request, err error) {
+	log.Printf("fastroute: %s %s (%s): %v", req.Method, req.URL.Path, Pattern(req), err)
+}
@@ -0,0 +1,245 @@
+// Package fastroutetest provides utilities for testing
+// fastroute routers: table driven routing assertions,
+// checks for leaked pooled parameters and a fuzz harness
+// cross-checking path matching against a reference
+// regular expression implementation.
+package fastroutetest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+// Route is the expected routing outcome of a request
+// by method and path, an entry of the table given to
+// AssertRoutes.
+type Route struct {
+	Method string // defaults to GET
+	Path   string // request path, may include a query string
+
+	// Pattern of the route expected to match the request,
+	// empty if the request must not be routed.
+	Pattern string
+
+	// Params expected to be bound, in order. Nil params
+	// are not checked.
+	Params fastroute.Params
+
+	// Status code of the response when the request is
+	// served by the router, zero if it should not be served.
+	Status int
+}
+
+func (r Route) String() string {
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	return method + " " + r.Path
+}
+
+// AssertRoutes routes every request in the table with
+// the router and reports mismatches of the expected
+// pattern and params. Routed requests are recycled
+// and then, if the Status is expected, served by the
+// router. After both, the request must not hold pooled
+// parameters anymore and must have its body restored.
+func AssertRoutes(t testing.TB, router fastroute.Router, table []Route) {
+	t.Helper()
+	for _, r := range table {
+		req, body := request(r)
+		h := router.Route(req)
+		switch {
+		case h == nil && r.Pattern != "":
+			t.Errorf("%s: expected to match pattern %s, but was not routed", r, r.Pattern)
+		case h != nil && r.Pattern == "":
+			t.Errorf("%s: did not expect to be routed, but matched pattern %s", r, fastroute.Pattern(req))
+		case h != nil:
+			if m := fastroute.Matched(req); m == nil || m.Pattern != r.Pattern {
+				t.Errorf("%s: expected to match pattern %s, but got %s", r, r.Pattern, fastroute.Pattern(req))
+			}
+			if ps := fastroute.Parameters(req); r.Params != nil && !equal(ps, r.Params) {
+				t.Errorf("%s: expected params %v, but got %v", r, r.Params, ps)
+			}
+		}
+		fastroute.Recycle(req)
+		assertRecycled(t, r, req, body)
+
+		if r.Status == 0 {
+			continue
+		}
+		req, body = request(r)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != r.Status {
+			t.Errorf("%s: expected status %d, but got %d", r, r.Status, w.Code)
+		}
+		assertRecycled(t, r, req, body)
+	}
+}
+
+// AssertRecycled reports an error if the request still
+// holds pooled parameters, which means it was routed,
+// but neither served nor recycled.
+func AssertRecycled(t testing.TB, req *http.Request) {
+	t.Helper()
+	if m := fastroute.Matched(req); m != nil {
+		t.Errorf("%s %s: parameters of route %s were not recycled", req.Method, req.URL.Path, m.Pattern)
+	}
+}
+
+func assertRecycled(t testing.TB, r Route, req *http.Request, body io.ReadCloser) {
+	t.Helper()
+	AssertRecycled(t, req)
+	if req.Body != body {
+		t.Errorf("%s: request body was not restored after parameters were recycled", r)
+	}
+}
+
+func request(r Route) (*http.Request, io.ReadCloser) {
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req := httptest.NewRequest(method, r.Path, nil)
+	return req, req.Body
+}
+
+func equal(a, b fastroute.Params) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Regexp compiles the path pattern, as accepted by
+// fastroute.New, to the reference regular expression,
+// matching the same paths and capturing the parameter
+// values in submatches, in order. It panics if the
+// pattern is not valid.
+//
+// A named parameter matches a segment, which may only
+// be empty if it is followed by a slash. A catch-all
+// parameter matches the rest of the path including the
+// leading slash. Trailing slash of the pattern must be
+// matched, unless it ends with a catch-all parameter.
+func Regexp(pattern string) *regexp.Regexp {
+	if _, err := fastroute.Compile(pattern, http.NotFoundHandler()); err != nil {
+		panic(err)
+	}
+
+	p := "/" + strings.TrimLeft(pattern, "/")
+	if strings.IndexAny(p, ":*") == -1 {
+		return regexp.MustCompile("(?s)^" + regexp.QuoteMeta(p) + "$")
+	}
+
+	ts := strings.HasSuffix(p, "/")
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	expr := "(?s)^"
+	for i, seg := range segments {
+		switch {
+		case strings.HasPrefix(seg, "*"):
+			return regexp.MustCompile(expr + "(/.*)$")
+		case strings.HasPrefix(seg, ":") && i == len(segments)-1 && !ts:
+			expr += "/([^/]+)"
+		case strings.HasPrefix(seg, ":"):
+			expr += "/([^/]*)"
+		default:
+			expr += regexp.QuoteMeta("/" + seg)
+		}
+	}
+	if ts {
+		expr += "/"
+	}
+	return regexp.MustCompile(expr + "$")
+}
+
+// Match routes the path with the route created by
+// fastroute.New from the pattern and reports an error
+// if the outcome or captured parameters differ from the
+// reference Regexp, or if parameters are not recycled
+// afterwards. Invalid patterns are skipped, as well
+// as patterns and paths which are not valid UTF-8, since
+// regular expressions cannot match such bytes exactly.
+func Match(t testing.TB, pattern, path string) {
+	t.Helper()
+	if !utf8.ValidString(pattern) || !utf8.ValidString(path) {
+		t.Skip("not valid UTF-8")
+	}
+	router, err := fastroute.Compile(pattern, http.NotFoundHandler())
+	if err != nil {
+		t.Skip(err)
+	}
+	re := Regexp(pattern)
+
+	// path is not parsed, so it may contain any byte
+	req := &http.Request{Method: http.MethodGet, URL: &url.URL{Path: path}, Header: make(http.Header)}
+	h := router.Route(req)
+	defer func() {
+		fastroute.Recycle(req)
+		if fastroute.Parameters(req) != nil || req.Body != nil {
+			t.Errorf("pattern %q: path %q parameters were not recycled", pattern, path)
+		}
+	}()
+
+	sub := re.FindStringSubmatch(path)
+	switch {
+	case h == nil && sub != nil:
+		t.Fatalf("pattern %q: path %q is not matched, but reference matches %q", pattern, path, sub[1:])
+	case h != nil && sub == nil:
+		t.Fatalf("pattern %q: path %q is matched with %v, but reference does not match", pattern, path, fastroute.Parameters(req))
+	case h != nil:
+		ps := fastroute.Parameters(req)
+		if len(ps) != len(sub)-1 {
+			t.Fatalf("pattern %q: path %q params %v, but reference captures %q", pattern, path, ps, sub[1:])
+		}
+		for i, p := range ps {
+			if p.Value != sub[i+1] {
+				t.Fatalf("pattern %q: path %q params %v, but reference captures %q", pattern, path, ps, sub[1:])
+			}
+		}
+	}
+}
+
+// Fuzz runs Match as the fuzz target with the seed corpus
+// of patterns and paths added to f, for example:
+//
+//	func FuzzRoutes(f *testing.F) {
+//		f.Add("/users/:id", "/users/1")
+//		fastroutetest.Fuzz(f)
+//	}
+func Fuzz(f *testing.F) {
+	for _, seed := range [][2]string{
+		{"/", "/"},
+		{"/users", "/users"},
+		{"/users/:id", "/users/1"},
+		{"/users/:id", "/users/"},
+		{"/users/:id/", "/users//"},
+		{"/users/:id/posts", "/users//posts"},
+		{"/files/*path", "/files/"},
+		{"/files/*path", "/files"},
+		{"/files/*path", "/files//a/b/"},
+		{"/a/:b/:c/", "/a/b/c/"},
+		{"/a/:b/:c/", "/a/b/c"},
+		{"/:a//b", "/x//b"},
+		{"/ünìcodé/:q", "/ünìcodé/ü"},
+	} {
+		f.Add(seed[0], seed[1])
+	}
+	f.Fuzz(func(t *testing.T, pattern, path string) {
+		Match(t, pattern, path)
+	})
+}
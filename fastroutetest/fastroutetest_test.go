@@ -0,0 +1,110 @@
+package fastroutetest_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+	"github.com/DATA-DOG/fastroute/fastroutetest"
+)
+
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertRoutes(t *testing.T) {
+	t.Parallel()
+	router := fastroute.Methods{
+		"GET": fastroute.Chain(
+			fastroute.New("/users", http.NotFoundHandler()),
+			fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {}),
+			fastroute.New("/files/*path", func(w http.ResponseWriter, req *http.Request) {}),
+		),
+	}
+
+	fastroutetest.AssertRoutes(t, router, []fastroutetest.Route{
+		{Path: "/users", Pattern: "/users", Params: fastroute.Params{}, Status: 404},
+		{Path: "/users/1?x=y", Pattern: "/users/:id", Params: fastroute.Params{{"id", "1"}}, Status: 200},
+		{Path: "/files/a/b", Pattern: "/files/*path", Params: fastroute.Params{{"path", "/a/b"}}},
+		{Path: "/users/1/posts", Status: 404},
+		{Method: "POST", Path: "/users"},
+	})
+}
+
+func TestAssertRoutesReportsMismatches(t *testing.T) {
+	t.Parallel()
+	router := fastroute.Chain(
+		fastroute.New("/users/:id", http.NotFoundHandler()),
+		fastroute.New("/posts", http.NotFoundHandler()),
+	)
+
+	rec := &recorder{TB: t}
+	fastroutetest.AssertRoutes(rec, router, []fastroutetest.Route{
+		{Path: "/users/1", Pattern: "/users/:name"},
+		{Path: "/users/1", Pattern: "/users/:id", Params: fastroute.Params{{"id", "2"}}},
+		{Path: "/posts"},
+		{Path: "/comments", Pattern: "/comments"},
+		{Path: "/posts", Pattern: "/posts", Status: 200},
+	})
+
+	expected := []string{
+		"GET /users/1: expected to match pattern /users/:name, but got /users/:id",
+		"GET /users/1: expected params [{id 2}], but got [{id 1}]",
+		"GET /posts: did not expect to be routed, but matched pattern /posts",
+		"GET /comments: expected to match pattern /comments, but was not routed",
+		"GET /posts: expected status 200, but got 404",
+	}
+	if fmt.Sprint(rec.errors) != fmt.Sprint(expected) {
+		t.Fatalf("unexpected errors: %q", rec.errors)
+	}
+}
+
+func TestAssertRecycled(t *testing.T) {
+	t.Parallel()
+	router := fastroute.New("/users/:id", http.NotFoundHandler())
+	req, _ := http.NewRequest("GET", "/users/1", nil)
+	router.Route(req)
+
+	rec := &recorder{TB: t}
+	fastroutetest.AssertRecycled(rec, req)
+	if len(rec.errors) != 1 || rec.errors[0] != "GET /users/1: parameters of route /users/:id were not recycled" {
+		t.Fatalf("expected leaked parameters to be reported, but got: %q", rec.errors)
+	}
+
+	fastroute.Recycle(req)
+	rec.errors = nil
+	fastroutetest.AssertRecycled(rec, req)
+	if len(rec.errors) != 0 {
+		t.Fatalf("unexpected errors: %q", rec.errors)
+	}
+}
+
+func TestRegexp(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		pattern, expr string
+	}{
+		{"/users", `(?s)^/users$`},
+		{"users/:id", `(?s)^/users/([^/]+)$`},
+		{"/users/:id/", `(?s)^/users/([^/]*)/$`},
+		{"/a.b/:c/*d", `(?s)^/a\.b/([^/]*)(/.*)$`},
+	}
+	for _, c := range cases {
+		if expr := fastroutetest.Regexp(c.pattern).String(); expr != c.expr {
+			t.Fatalf("expected pattern %s to compile to %s, but got: %s", c.pattern, c.expr, expr)
+		}
+	}
+}
+
+func FuzzMatch(f *testing.F) {
+	f.Add("/repos/:owner/:repo/issues/:number", "/repos/a/b/issues/1")
+	fastroutetest.Fuzz(f)
+}
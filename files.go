@@ -0,0 +1,203 @@
+package fastroute
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CacheControl metadata key sets Cache-Control header
+// for files served by Files router. Files are revalidated
+// using ETag by default ("no-cache").
+var CacheControl = NewKey[string]("cachecontrol")
+
+// Files creates Router serving files from fs, for example
+// http.Dir or embed.FS wrapped by http.FS, by the catch-all
+// parameter of the pattern, which must be the last segment:
+//
+//	fastroute.Files("/static/*filepath", http.FS(assets))
+//
+// Paths trying to escape the file system root are rejected.
+// Directories are served by their "index.html" file, without
+// listings. If the client accepts compressed encoding and a
+// precompressed ".br" or ".gz" sibling of the file exists,
+// it is served instead, typed by the original file extension
+// or content. Responses have ETag, Last-Modified
+// and Cache-Control headers, conditional and range requests
+// are supported.
+func Files(pattern string, fs http.FileSystem) Router {
+	return files(pattern, &fileServer{fs: fs})
+}
+
+func files(pattern string, server *fileServer) Router {
+	if seg := pattern[strings.LastIndexByte(pattern, '/')+1:]; !strings.HasPrefix(seg, "*") {
+		panic("files pattern must end with catch-all parameter: " + pattern)
+	}
+	return New(pattern, server)
+}
+
+type fileServer struct {
+	fs     http.FileSystem
+	hashes sync.Map // content hashes of files without modification time
+}
+
+var encodings = []struct{ name, ext string }{{"br", ".br"}, {"gzip", ".gz"}}
+
+func (s *fileServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := Parameters(req)
+	name, ok := cleanName(params[len(params)-1].Value) // catch-all is the last one
+	if !ok {
+		http.Error(w, "invalid URL path", http.StatusBadRequest)
+		return
+	}
+	s.serve(w, req, name)
+}
+
+func (s *fileServer) serve(w http.ResponseWriter, req *http.Request, name string) {
+	f, info, err := s.open(name)
+	if err != nil {
+		http.NotFound(w, req)
+		return
+	}
+	defer f.Close()
+
+	if info.IsDir() {
+		if !strings.HasSuffix(req.URL.Path, "/") {
+			http.Redirect(w, req, path.Base(req.URL.Path)+"/", http.StatusMovedPermanently)
+			return
+		}
+		f.Close()
+		name = path.Join(name, "index.html")
+		if f, info, err = s.open(name); err != nil || info.IsDir() {
+			http.NotFound(w, req)
+			return
+		}
+		defer f.Close()
+	}
+
+	header := w.Header()
+	header.Add("Vary", "Accept-Encoding")
+	if cc, ok := CacheControl.Get(req); ok {
+		header.Set("Cache-Control", cc)
+	} else {
+		header.Set("Cache-Control", "no-cache")
+	}
+	// resolved by the original file, compressed sibling would be typed by its extension
+	header.Set("Content-Type", contentType(name, f))
+
+	content, etag := f, ""
+	for _, enc := range encodings {
+		if !acceptsEncoding(req, enc.name) {
+			continue
+		}
+		cf, cinfo, err := s.open(name + enc.ext)
+		if err != nil || cinfo.IsDir() {
+			continue
+		}
+		defer cf.Close()
+		header.Set("Content-Encoding", enc.name)
+		content, info, etag = cf, cinfo, "-"+enc.name
+		name += enc.ext
+		break
+	}
+
+	if tag, err := s.etag(name, content, info); err == nil {
+		header.Set("ETag", `"`+tag+etag+`"`)
+	}
+	http.ServeContent(w, req, name, info.ModTime(), content)
+}
+
+// resolves content type by the file extension, or by sniffing
+// its content the same as http.ServeContent does
+func contentType(name string, f http.File) string {
+	if ctype := mime.TypeByExtension(path.Ext(name)); ctype != "" {
+		return ctype
+	}
+	var buf [512]byte
+	n, _ := io.ReadFull(f, buf[:])
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "application/octet-stream"
+	}
+	return http.DetectContentType(buf[:n])
+}
+
+func (s *fileServer) open(name string) (http.File, os.FileInfo, error) {
+	f, err := s.fs.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, info, nil
+}
+
+// resolves entity tag by size and modification time, or content hash
+// if the modification time is not known, like for embed.FS
+func (s *fileServer) etag(name string, f http.File, info os.FileInfo) (string, error) {
+	if !info.ModTime().IsZero() {
+		return strconv.FormatInt(info.Size(), 36) + "-" + strconv.FormatInt(info.ModTime().UnixNano(), 36), nil
+	}
+	if tag, ok := s.hashes.Load(name); ok {
+		return tag.(string), nil
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	tag := hex.EncodeToString(h.Sum(nil)[:16])
+	s.hashes.Store(name, tag)
+	return tag, nil
+}
+
+// cleans requested file name, unless it tries to escape the root
+func cleanName(name string) (string, bool) {
+	if strings.Contains(name, "\x00") || strings.Contains(name, "\\") || containsDotDot(name) {
+		return "", false
+	}
+	return path.Clean("/" + name), true
+}
+
+func containsDotDot(p string) bool {
+	for _, seg := range strings.Split(p, "/") {
+		if seg == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+func acceptsEncoding(req *http.Request, encoding string) bool {
+	for _, ae := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		params := strings.Split(ae, ";")
+		if strings.TrimSpace(params[0]) != encoding {
+			continue
+		}
+		for _, p := range params[1:] {
+			if q := strings.TrimSpace(p); q == "q=0" || q == "q=0.0" || q == "q=0.00" || q == "q=0.000" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
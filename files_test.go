@@ -0,0 +1,110 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestFilesRouter(t *testing.T) {
+	t.Parallel()
+	modified := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	fs := fstest.MapFS{
+		"app.js":          {Data: []byte("console.log(1)")},
+		"app.js.gz":       {Data: []byte("gzipped")},
+		"app.js.br":       {Data: []byte("brotli")},
+		"style.css":       {Data: []byte("body{}"), ModTime: modified},
+		"docs/index.html": {Data: []byte("<h1>docs</h1>")},
+		"empty/.keep":     {Data: []byte{}},
+		"LICENSE":         {Data: []byte("BSD license")},
+		"LICENSE.gz":      {Data: []byte("gzipped")},
+		"blob":            {Data: []byte{0x00, 0x01, 0x02}},
+	}
+	router := fastroute.Chain(
+		fastroute.With(fastroute.Files("/static/*filepath", http.FS(fs)), fastroute.CacheControl.Value("public, max-age=60")),
+		fastroute.Files("/assets/*filepath", http.FS(fs)),
+	)
+
+	cases := []struct {
+		path, encoding string
+		code           int
+		body           string
+		headers        map[string]string
+	}{
+		{"/static/app.js", "", 200, "console.log(1)", map[string]string{
+			"Content-Type":     "text/javascript; charset=utf-8",
+			"Cache-Control":    "public, max-age=60",
+			"Content-Encoding": "",
+		}},
+		{"/static/app.js", "gzip, deflate, br", 200, "brotli", map[string]string{
+			"Content-Type":     "text/javascript; charset=utf-8",
+			"Content-Encoding": "br",
+		}},
+		{"/static/app.js", "gzip, br;q=0", 200, "gzipped", map[string]string{"Content-Encoding": "gzip"}},
+		{"/assets/style.css", "", 200, "body{}", map[string]string{
+			"Cache-Control": "no-cache",
+			"Last-Modified": "Wed, 01 Jan 2020 00:00:00 GMT",
+		}},
+		{"/static/LICENSE", "gzip", 200, "gzipped", map[string]string{
+			"Content-Type":     "text/plain; charset=utf-8",
+			"Content-Encoding": "gzip",
+		}},
+		{"/static/blob", "", 200, "\x00\x01\x02", map[string]string{"Content-Type": "application/octet-stream"}},
+		{"/static/docs/", "", 200, "<h1>docs</h1>", nil},
+		{"/static/docs", "", 301, "", map[string]string{"Location": "/static/docs/"}},
+		{"/static/empty/", "", 404, "404 page not found\n", nil},
+		{"/static/missing.js", "", 404, "404 page not found\n", nil},
+		{"/static/../files_test.go", "", 400, "invalid URL path\n", nil},
+	}
+
+	for _, c := range cases {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.URL.Path = c.path // keep unclean path
+		req.Header.Set("Accept-Encoding", c.encoding)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != c.code || (c.body != "" && w.Body.String() != c.body) {
+			t.Fatalf("unexpected response for %s: %d %q", c.path, w.Code, w.Body.String())
+		}
+		for key, val := range c.headers {
+			if act := w.Header().Get(key); act != val {
+				t.Fatalf("unexpected header %s: %q, expected: %q for: %s", key, act, val, c.path)
+			}
+		}
+	}
+}
+
+func TestFilesETagRevalidation(t *testing.T) {
+	t.Parallel()
+	router := fastroute.Files("/*filepath", http.FS(fstest.MapFS{"a.txt": {Data: []byte("a")}}))
+
+	req, _ := http.NewRequest("GET", "/a.txt", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	etag := w.Header().Get("ETag")
+	if w.Code != 200 || etag == "" {
+		t.Fatalf("expected ETag, but got: %d %q", w.Code, etag)
+	}
+
+	req, _ = http.NewRequest("GET", "/a.txt", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("expected not modified, but got: %d", w.Code)
+	}
+}
+
+func TestFilesPatternMustEndWithCatchAll(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	fastroute.Files("/static/:name", http.Dir("."))
+}
@@ -0,0 +1,88 @@
+package fastroute_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+	"github.com/DATA-DOG/fastroute/fastroutetest"
+)
+
+// reference model of pattern validation: every segment having
+// a param sign must be a single named param, catch-all only last
+func validModel(pattern string) bool {
+	p := "/" + strings.TrimLeft(pattern, "/")
+	segs := strings.Split(strings.Trim(p, "/"), "/")
+	for i, seg := range segs {
+		switch signs := strings.Count(seg, ":") + strings.Count(seg, "*"); {
+		case signs == 0:
+		case signs > 1, len(seg) < 2, seg[0] != ':' && seg[0] != '*':
+			return false
+		case seg[0] == '*' && i != len(segs)-1:
+			return false
+		}
+	}
+	return true
+}
+
+func FuzzNew(f *testing.F) {
+	for _, seed := range []string{"/", "", "/users/:id", "/files/*path", "/a:b", "/:", "/*a/b", "/:a:b", "users//:id/"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, pattern string) {
+		_, err := fastroute.Compile(pattern, http.NotFoundHandler())
+		if valid := validModel(pattern); valid != (err == nil) {
+			t.Fatalf("pattern %q is expected to be valid: %t, but got: %v", pattern, valid, err)
+		}
+
+		panicked := func() (v interface{}) {
+			defer func() { v = recover() }()
+			fastroute.New(pattern, http.NotFoundHandler())
+			return nil
+		}()
+		if (panicked != nil) != (err != nil) {
+			t.Fatalf("pattern %q: New panics with %v, while Compile returns %v", pattern, panicked, err)
+		}
+		if err == nil {
+			return
+		}
+
+		var perr *fastroute.PatternError
+		if !errors.As(err, &perr) {
+			t.Fatalf("pattern %q: expected *PatternError, but got: %T", pattern, err)
+		}
+		segs := strings.Split(strings.Trim(perr.Pattern, "/"), "/")
+		if perr.Segment < 0 || perr.Segment >= len(segs) {
+			t.Fatalf("pattern %q: segment %d is out of range", pattern, perr.Segment)
+		}
+		for _, reason := range []error{
+			fastroute.ErrParamPosition,
+			fastroute.ErrParamUnnamed,
+			fastroute.ErrCatchAllPosition,
+			fastroute.ErrParamsPerSegment,
+		} {
+			if errors.Is(err, reason) {
+				return
+			}
+		}
+		t.Fatalf("pattern %q: unexpected reason: %v", pattern, err)
+	})
+}
+
+func FuzzMatch(f *testing.F) {
+	for _, seed := range [][2]string{
+		{"/users/:id", "/users/1"},
+		{"/users/:id/", "/users//"},
+		{"/files/*path", "/files/"},
+		{"/files/*path", "/files"},
+		{"/a//:b", "/a//b"},
+		{"/:a/:b", "//"},
+	} {
+		f.Add(seed[0], seed[1])
+	}
+	f.Fuzz(func(t *testing.T, pattern, path string) {
+		fastroutetest.Match(t, pattern, path)
+	})
+}
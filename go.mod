@@ -0,0 +1,3 @@
+module github.com/DATA-DOG/fastroute
+
+go 1.20
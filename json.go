@@ -0,0 +1,112 @@
+package fastroute
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Problem is RFC 7807 problem details document, which
+// describes the error responded by JSON handlers. It may
+// be returned as an error to control the response.
+type Problem struct {
+	Type     string `json:"type,omitempty"` // "about:blank" if empty
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// JSON adapts fn to http.Handler, which can be given to New.
+// The result of fn is encoded as JSON document, nil result
+// is responded with 204 No Content:
+//
+//	fastroute.New("/users/:id", fastroute.JSON(func(req *http.Request) (interface{}, error) {
+//		return users.Find(fastroute.Parameters(req).ByName("id"))
+//	}))
+//
+// Errors are mapped to the status code by StatusOf and responded
+// with application/problem+json document. The error message is
+// given as the problem detail for client errors, while server
+// errors are hidden and logged along with the matched route
+// pattern, see JSONOptions.
+func JSON(fn func(*http.Request) (interface{}, error)) http.Handler {
+	return JSONOptions{}.Handler(fn)
+}
+
+// JSONOptions configure handlers created by JSON.
+type JSONOptions struct {
+	// ErrorLog is called with server errors. The matched
+	// route is available by Pattern or Matched. If nil,
+	// errors are logged by the standard logger along with
+	// the route pattern.
+	ErrorLog func(*http.Request, error)
+}
+
+// Handler adapts fn to http.Handler, the same as JSON.
+func (o JSONOptions) Handler(fn func(*http.Request) (interface{}, error)) http.Handler {
+	h := &jsonHandler{fn: fn, errorLog: o.ErrorLog}
+	if h.errorLog == nil {
+		h.errorLog = logError
+	}
+	return h
+}
+
+type jsonHandler struct {
+	fn       func(*http.Request) (interface{}, error)
+	errorLog func(*http.Request, error)
+}
+
+func (h *jsonHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	v, err := h.fn(req)
+	if err != nil {
+		h.problem(w, req, err)
+		return
+	}
+	if v == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		h.problem(w, req, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(data, '\n'))
+}
+
+func (h *jsonHandler) problem(w http.ResponseWriter, req *http.Request, err error) {
+	status := StatusOf(err)
+	var p Problem
+	if typed := (*Problem)(nil); errors.As(err, &typed) {
+		p = *typed
+	} else if status < http.StatusInternalServerError {
+		p.Detail = err.Error()
+	}
+	if status >= http.StatusInternalServerError {
+		h.errorLog(req, err)
+	}
+
+	p.Status = status
+	if p.Title == "" {
+		p.Title = http.StatusText(status)
+	}
+	if p.Instance == "" {
+		p.Instance = req.URL.Path
+	}
+
+	data, _ := json.Marshal(p) // cannot fail
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
@@ -0,0 +1,105 @@
+package fastroute_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestJSONAdapter(t *testing.T) {
+	t.Parallel()
+	var logs bytes.Buffer
+	opts := fastroute.JSONOptions{ErrorLog: func(req *http.Request, err error) {
+		fmt.Fprintf(&logs, "%s %s (%s): %v\n", req.Method, req.URL.Path, fastroute.Pattern(req), err)
+	}}
+
+	type user struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	router := fastroute.Chain(
+		fastroute.New("/users/:id", opts.Handler(func(req *http.Request) (interface{}, error) {
+			switch id := fastroute.Parameters(req).ByName("id"); id {
+			case "1":
+				return user{ID: id, Name: "gopher"}, nil
+			case "gone":
+				return nil, &fastroute.Problem{Type: "https://example.com/gone", Title: "User is gone", Status: 410}
+			case "db":
+				return nil, errors.New("connection refused")
+			case "chan":
+				return make(chan int), nil
+			default:
+				return nil, fmt.Errorf("user %s: %w", id, fastroute.ErrNotFound)
+			}
+		})),
+		fastroute.New("/users", fastroute.JSON(func(req *http.Request) (interface{}, error) {
+			switch req.Method {
+			case "PUT":
+				return nil, fmt.Errorf("email is taken: %w", fastroute.ErrConflict)
+			case "POST":
+				return nil, fmt.Errorf("name is required: %w", fastroute.ErrValidation)
+			}
+			return nil, nil
+		})),
+	)
+
+	cases := []struct {
+		method, path string
+		code         int
+		ctype, body  string
+	}{
+		{"GET", "/users/1", 200, "application/json", `{"id":"1","name":"gopher"}`},
+		{"GET", "/users/2", 404, "application/problem+json", `{"title":"Not Found","status":404,"detail":"user 2: not found","instance":"/users/2"}`},
+		{"GET", "/users/gone", 410, "application/problem+json", `{"type":"https://example.com/gone","title":"User is gone","status":410,"instance":"/users/gone"}`},
+		{"GET", "/users/db", 500, "application/problem+json", `{"title":"Internal Server Error","status":500,"instance":"/users/db"}`},
+		{"GET", "/users/chan", 500, "application/problem+json", `{"title":"Internal Server Error","status":500,"instance":"/users/chan"}`},
+		{"PUT", "/users", 409, "application/problem+json", `{"title":"Conflict","status":409,"detail":"email is taken: conflict","instance":"/users"}`},
+		{"POST", "/users", 422, "application/problem+json", `{"title":"Unprocessable Entity","status":422,"detail":"name is required: validation failed","instance":"/users"}`},
+		{"GET", "/users", 204, "", ``},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, c.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != c.code || w.Header().Get("Content-Type") != c.ctype || strings.TrimSpace(w.Body.String()) != c.body {
+			t.Fatalf("unexpected response for %s %s: %d %s %s", c.method, c.path, w.Code, w.Header().Get("Content-Type"), w.Body.String())
+		}
+	}
+
+	expected := "GET /users/db (/users/:id): connection refused\n" +
+		"GET /users/chan (/users/:id): json: unsupported type: chan int\n"
+	if logs.String() != expected {
+		t.Fatalf("unexpected error logs: %q", logs.String())
+	}
+
+	if status := fastroute.StatusOf(&fastroute.BindError{Param: "id", Err: errors.New("invalid syntax")}); status != 400 {
+		t.Fatalf("expected bind error to be bad request, but got: %d", status)
+	}
+}
+
+// not parallel, since the standard logger output is replaced
+func TestJSONLogsByStandardLoggerByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	router := fastroute.New("/users/:id", fastroute.JSON(func(req *http.Request) (interface{}, error) {
+		return nil, errors.New("database is down")
+	}))
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	expected := "fastroute: GET /users/1 (/users/:id): database is down\n"
+	if w.Code != http.StatusInternalServerError || !strings.HasSuffix(buf.String(), expected) {
+		t.Fatalf("unexpected response %d and log: %q", w.Code, buf.String())
+	}
+}
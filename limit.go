@@ -0,0 +1,176 @@
+package fastroute
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit describes request limits of a route.
+// Zero values mean unlimited.
+type RateLimit struct {
+	Rate        float64 // requests per second, refilling the token bucket
+	Burst       int     // token bucket size, at least 1
+	MaxInFlight int     // maximum number of requests served concurrently
+	Param       string  // optional path parameter, which value gets its own limits
+}
+
+// RateLimits metadata key overrides Limiter defaults
+// for the route it is attached to.
+var RateLimits = NewKey[RateLimit]("ratelimit")
+
+// Limiter enforces request limits for every route pattern,
+// or path parameter value if RateLimit.Param is set, of the
+// routers limited by Limit. Requests over the limits are
+// rejected with 429 Too Many Requests and Retry-After header.
+type Limiter struct {
+	Default RateLimit        // limits for routes without RateLimits metadata
+	Now     func() time.Time // clock, time.Now if nil
+
+	mu      sync.Mutex
+	buckets map[bucketKey]*bucket
+	sweep   int // number of buckets to trigger idle bucket cleanup
+}
+
+type bucketKey struct {
+	route *Route
+	param string
+}
+
+type bucket struct {
+	limit    RateLimit
+	tokens   float64
+	last     time.Time
+	inflight int
+}
+
+// Limit wraps router in order to enforce limits for every
+// served request it routes. Limits are looked up from the
+// route matched, so the router should be composed of routes
+// created by New.
+func (l *Limiter) Limit(router Router) Router {
+	return &limited{router: router, limiter: l}
+}
+
+type limited struct {
+	router  Router
+	limiter *Limiter
+}
+
+func (l *limited) Route(req *http.Request) http.Handler {
+	h := l.router.Route(req)
+	route := Matched(req)
+	if h == nil || route == nil {
+		return h
+	}
+
+	limit, ok := RateLimits.Of(route)
+	if !ok {
+		limit = l.limiter.Default
+	}
+	key := bucketKey{route: route}
+	if limit.Param != "" {
+		key.param = Parameters(req).ByName(limit.Param)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		retry, ok := l.limiter.acquire(key, limit)
+		if !ok {
+			Recycle(req) // matched handler is not served
+			w.Header().Set("Retry-After", strconv.Itoa(retry))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		defer l.limiter.release(key, limit)
+		h.ServeHTTP(w, req)
+	})
+}
+
+func (l *limited) unwrap() Router              { return l.router }
+func (l *limited) rewrap(router Router) Router { return l.limiter.Limit(router) }
+func (l *limited) String() string              { return "Limiter" }
+
+func (l *limited) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h := l.Route(req); h != nil {
+		h.ServeHTTP(w, req)
+	} else {
+		http.NotFound(w, req)
+	}
+}
+
+func (l *Limiter) now() time.Time {
+	if l.Now != nil {
+		return l.Now()
+	}
+	return time.Now()
+}
+
+// takes a token and in-flight slot, otherwise returns seconds to retry after
+func (l *Limiter) acquire(key bucketKey, limit RateLimit) (int, bool) {
+	if limit.Rate <= 0 && limit.MaxInFlight <= 0 {
+		return 0, true
+	}
+	burst := limit.burst()
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.buckets == nil {
+		l.buckets = make(map[bucketKey]*bucket)
+	}
+	b := l.buckets[key]
+	if b == nil {
+		l.cleanup(now)
+		b = &bucket{limit: limit, tokens: burst, last: now}
+		l.buckets[key] = b
+	}
+
+	if limit.MaxInFlight > 0 && b.inflight >= limit.MaxInFlight {
+		return 1, false
+	}
+
+	if limit.Rate > 0 {
+		b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+		b.last = now
+		if b.tokens < 1 {
+			return int(math.Ceil((1 - b.tokens) / limit.Rate)), false
+		}
+		b.tokens--
+	}
+	b.inflight++
+	return 0, true
+}
+
+func (l *Limiter) release(key bucketKey, limit RateLimit) {
+	if limit.Rate <= 0 && limit.MaxInFlight <= 0 {
+		return
+	}
+	l.mu.Lock()
+	if b := l.buckets[key]; b != nil {
+		b.inflight--
+	}
+	l.mu.Unlock()
+}
+
+// removes idle buckets, which would be full by now, when the number
+// of buckets doubles, since buckets keyed by parameter may grow unbounded
+func (l *Limiter) cleanup(now time.Time) {
+	if len(l.buckets) < l.sweep {
+		return
+	}
+	for key, b := range l.buckets {
+		if b.inflight == 0 && b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate >= b.limit.burst() {
+			delete(l.buckets, key)
+		}
+	}
+	l.sweep = 2*len(l.buckets) + 64
+}
+
+func (limit RateLimit) burst() float64 {
+	if limit.Burst < 1 {
+		return 1
+	}
+	return float64(limit.Burst)
+}
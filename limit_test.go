@@ -0,0 +1,96 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestLimiterTokenBucketPerRoute(t *testing.T) {
+	t.Parallel()
+	now := time.Unix(0, 0)
+	limiter := &fastroute.Limiter{
+		Default: fastroute.RateLimit{Rate: 0.5, Burst: 2},
+		Now:     func() time.Time { return now },
+	}
+
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	router := limiter.Limit(fastroute.Chain(
+		fastroute.New("/users/:id", handler),
+		fastroute.With(fastroute.New("/tenants/:tenant/items", handler), fastroute.RateLimits.Value(fastroute.RateLimit{
+			Rate:  1,
+			Param: "tenant",
+		})),
+	))
+
+	do := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters to be recycled for: %s", path)
+		}
+		return w
+	}
+
+	// burst of two, any user id shares the route bucket
+	for i, path := range []string{"/users/1", "/users/2"} {
+		if w := do(path); w.Code != 200 {
+			t.Fatalf("expected request %d to be allowed, but got: %d", i, w.Code)
+		}
+	}
+	w := do("/users/3")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "2" {
+		t.Fatalf("expected request to be limited, but got: %d retry after: %s", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	now = now.Add(2 * time.Second)
+	if w := do("/users/3"); w.Code != 200 {
+		t.Fatalf("expected request to be allowed after refill, but got: %d", w.Code)
+	}
+
+	// buckets by tenant param
+	if w := do("/tenants/a/items"); w.Code != 200 {
+		t.Fatalf("expected first tenant request to be allowed, but got: %d", w.Code)
+	}
+	if w := do("/tenants/a/items"); w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1" {
+		t.Fatalf("expected second tenant request to be limited, but got: %d", w.Code)
+	}
+	if w := do("/tenants/b/items"); w.Code != 200 {
+		t.Fatalf("expected other tenant request to be allowed, but got: %d", w.Code)
+	}
+}
+
+func TestLimiterMaxInFlight(t *testing.T) {
+	t.Parallel()
+	limiter := &fastroute.Limiter{Default: fastroute.RateLimit{MaxInFlight: 1}}
+
+	var nested *httptest.ResponseRecorder
+	var router fastroute.Router
+	router = limiter.Limit(fastroute.New("/slow", func(w http.ResponseWriter, req *http.Request) {
+		if nested == nil {
+			// while this request is in flight
+			inner, _ := http.NewRequest("GET", "/slow", nil)
+			nested = httptest.NewRecorder()
+			router.ServeHTTP(nested, inner)
+		}
+	}))
+
+	req, _ := http.NewRequest("GET", "/slow", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != 200 || nested.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected concurrent request to be limited, but got: %d and %d", w.Code, nested.Code)
+	}
+
+	req, _ = http.NewRequest("GET", "/slow", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected in-flight slot to be released, but got: %d", w.Code)
+	}
+}
@@ -0,0 +1,165 @@
+package fastroute
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// Key identifies typed route metadata, like route
+// auth scopes, rate limit class or deprecation flag.
+// Keys are compared by identity, so they are usually
+// declared as package variables:
+//
+//	var Scopes = fastroute.NewKey[[]string]("scopes")
+//
+//	router := fastroute.With(fastroute.New("/users/:id", handler), Scopes.Value([]string{"users:read"}))
+type Key[T any] struct {
+	name string
+}
+
+// NewKey creates metadata key, the name
+// is only used for debugging.
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+func (k *Key[T]) String() string {
+	return k.name
+}
+
+// Value binds v to the key, in order to
+// attach it to routes using With.
+func (k *Key[T]) Value(v T) Meta {
+	return Meta{key: k, value: v}
+}
+
+// Get returns metadata value of the route,
+// which has matched the request.
+func (k *Key[T]) Get(req *http.Request) (T, bool) {
+	return k.Of(Matched(req))
+}
+
+// Of returns metadata value attached to the route.
+func (k *Key[T]) Of(route *Route) (v T, ok bool) {
+	if route == nil {
+		return v, false
+	}
+	// the last attached value takes precedence
+	for i := len(route.meta) - 1; i >= 0; i-- {
+		if route.meta[i].key == k {
+			v, _ = route.meta[i].value.(T) // nil of interface type T
+			return v, true
+		}
+	}
+	return v, false
+}
+
+// Meta is a metadata value bound to the Key.
+type Meta struct {
+	key   interface{}
+	value interface{}
+}
+
+func (m Meta) String() string {
+	return fmt.Sprintf("%v=%v", m.key, m.value)
+}
+
+// With attaches metadata to the routes created by New or
+// Named. Given composed router, like Chain or Methods, the
+// metadata is attached to every route it is composed of.
+// Routers are not modified, instead the new Router is
+// returned.
+//
+// With panics, if the router or any of the routers
+// it is composed of is not known to fastroute, for
+// example RouterFunc.
+func With(router Router, meta ...Meta) Router {
+	switch r := router.(type) {
+	case *route:
+		info := *r.info
+		info.meta = append(append([]Meta(nil), r.info.meta...), meta...)
+		return newRoute(&info, r.handler, r.segments)
+	case chain:
+		routes := make(chain, len(r))
+		for i := range r {
+			routes[i] = With(r[i], meta...)
+		}
+		return routes
+	case Methods:
+		routes := make(Methods, len(r))
+		for method := range r {
+			routes[method] = With(r[method], meta...)
+		}
+		return routes
+	case *versioned:
+		v := r.Versioning
+		v.Versions = make([]Version, len(r.Versions))
+		for i, version := range r.Versions {
+			v.Versions[i] = Version{Name: version.Name, Router: With(version.Router, meta...)}
+		}
+		return Versioned(v)
+	case wrapper:
+		return r.rewrap(With(r.unwrap(), meta...))
+	}
+	panic(fmt.Sprintf("cannot attach metadata to: %T", router))
+}
+
+// wrapper is implemented by Routers, which decorate
+// another Router, like Metrics.Instrument or Hooked
+type wrapper interface {
+	Router
+	fmt.Stringer
+	unwrap() Router
+	rewrap(Router) Router // decorates another router the same way
+}
+
+// WalkFunc is called by Walk for every route. Method is
+// empty unless the route is composed into Methods.
+type WalkFunc func(method string, route *Route) error
+
+// Walk calls fn for every route, created by New or Named,
+// the router is composed of, in the order the routes are
+// matched. Methods are walked in alphabetical order.
+// Routers unknown to fastroute, like RouterFunc, are
+// skipped. Walk stops at the first error returned by fn.
+func Walk(router Router, fn WalkFunc) error {
+	return walk("", router, fn)
+}
+
+func walk(method string, router Router, fn WalkFunc) error {
+	switch r := router.(type) {
+	case *route:
+		return fn(method, r.info)
+	case chain:
+		for _, sub := range r {
+			if err := walk(method, sub, fn); err != nil {
+				return err
+			}
+		}
+	case Methods:
+		methods := make([]string, 0, len(r))
+		for m := range r {
+			methods = append(methods, m)
+		}
+		sort.Strings(methods)
+		for _, m := range methods {
+			if err := walk(m, r[m], fn); err != nil {
+				return err
+			}
+		}
+	case *Atomic:
+		if current := r.Load(); current != nil {
+			return walk(method, current, fn)
+		}
+	case *versioned:
+		for _, version := range r.Versions {
+			if err := walk(method, version.Router, fn); err != nil {
+				return err
+			}
+		}
+	case wrapper:
+		return walk(method, r.unwrap(), fn)
+	}
+	return nil
+}
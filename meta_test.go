@@ -0,0 +1,108 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+var (
+	scopes     = fastroute.NewKey[[]string]("scopes")
+	deprecated = fastroute.NewKey[bool]("deprecated")
+)
+
+func TestRouteMetadata(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		s, _ := scopes.Get(req)
+		d, _ := deprecated.Get(req)
+		fmt.Fprintf(w, "%v %v", s, d)
+	}
+
+	users := fastroute.New("/users/:id", handler)
+	router := fastroute.Chain(
+		fastroute.With(
+			fastroute.Chain(users, fastroute.New("/users", handler)),
+			scopes.Value([]string{"users:read"}),
+		),
+		fastroute.With(fastroute.New("/old", handler), deprecated.Value(true), scopes.Value(nil), scopes.Value([]string{"admin"})),
+		users,
+	)
+
+	cases := map[string]string{
+		"/users/1": "[users:read] false",
+		"/users":   "[users:read] false",
+		"/old":     "[admin] true",
+	}
+	for path, expected := range cases {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Body.String() != expected {
+			t.Fatalf("unexpected metadata: %s for: %s, expected: %s", w.Body.String(), path, expected)
+		}
+	}
+
+	// original route is not modified
+	req, _ := http.NewRequest("GET", "/users/1", nil)
+	w := httptest.NewRecorder()
+	users.ServeHTTP(w, req)
+	if w.Body.String() != "[] false" {
+		t.Fatalf("expected original route without metadata, but got: %s", w.Body.String())
+	}
+}
+
+func TestRouteMetadataNilInterfaceValue(t *testing.T) {
+	t.Parallel()
+	cause := fastroute.NewKey[error]("cause")
+	router := fastroute.With(fastroute.New("/users", http.NotFoundHandler()), cause.Value(nil))
+
+	req, _ := http.NewRequest("GET", "/users", nil)
+	router.Route(req)
+	defer fastroute.Recycle(req)
+	if err, ok := cause.Get(req); err != nil || !ok {
+		t.Fatalf("expected nil value to be attached, but got: %v %t", err, ok)
+	}
+}
+
+func TestWalkComposedRouters(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	router := fastroute.Methods{
+		"POST": fastroute.New("/users", handler),
+		"GET": fastroute.Chain(
+			fastroute.Named("user", "/users/:id", handler),
+			fastroute.RouterFunc(func(req *http.Request) http.Handler { return nil }),
+			fastroute.NewAtomic(fastroute.With(fastroute.New("/users", handler), deprecated.Value(true))),
+		),
+	}
+
+	var walked []string
+	err := fastroute.Walk(router, func(method string, route *fastroute.Route) error {
+		d, _ := deprecated.Of(route)
+		walked = append(walked, fmt.Sprintf("%s %s %s %v", method, route.Pattern, route.Name, d))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "GET /users/:id user false,GET /users  true,POST /users  false"
+	if actual := strings.Join(walked, ","); actual != expected {
+		t.Fatalf("unexpected walk: %s, expected: %s", actual, expected)
+	}
+}
+
+func TestWithPanicsOnUnknownRouter(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if err := recover(); err == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	fastroute.With(fastroute.RouterFunc(func(req *http.Request) http.Handler { return nil }), deprecated.Value(true))
+}
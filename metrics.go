@@ -0,0 +1,302 @@
+package fastroute
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Pattern labels used by Metrics for requests which
+// were not routed, and for requests routed by routers
+// without path patterns, like RouterFunc. OtherMethod
+// labels requests with non standard methods.
+const (
+	Unmatched   = "unmatched"
+	Unpatterned = "unpatterned"
+	OtherMethod = "OTHER"
+)
+
+// standard methods, any other is labeled by OtherMethod
+var metricMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true,
+	http.MethodPut: true, http.MethodPatch: true, http.MethodDelete: true,
+	http.MethodConnect: true, http.MethodOptions: true, http.MethodTrace: true,
+}
+
+// DefaultBuckets are latency histogram buckets
+// in seconds, used by Metrics if none are given.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Metrics collects request count by method, pattern and
+// status together with request latency histogram by method
+// and pattern, for routers instrumented by Instrument.
+//
+// Route patterns are used as labels instead of request paths
+// in order to keep the number of series bounded. Metrics is
+// also http.Handler, which exposes collected metrics in the
+// Prometheus text format.
+type Metrics struct {
+	// Buckets are upper bounds of latency histogram in seconds,
+	// sorted in increasing order. DefaultBuckets if nil. They
+	// are read once, when the first request is recorded.
+	Buckets []float64
+
+	mu        sync.Mutex
+	bounds    []float64 // buckets in use
+	requests  map[requestLabels]uint64
+	latencies map[latencyLabels]*histogram
+}
+
+type requestLabels struct {
+	method, pattern string
+	status          int
+}
+
+type latencyLabels struct {
+	method, pattern string
+}
+
+type histogram struct {
+	counts []uint64 // cumulative counts per bucket
+	count  uint64
+	sum    float64
+}
+
+// Instrument wraps router in order to record metrics for every
+// served request it routes. Requests which cannot be routed are
+// passed on as nil handler, so the following routers in the Chain
+// may still match. Only when the instrumented router serves
+// the request by itself, unmatched request is recorded with
+// Unmatched pattern label.
+//
+// The pattern is resolved when the request is routed, so it is
+// the same for static and dynamic routes created by New. Other
+// routers, which are not using path patterns, are labeled by
+// Unpatterned and non standard methods by OtherMethod, in order
+// to keep the number of series bounded.
+func (m *Metrics) Instrument(router Router) Router {
+	return &instrumented{router: router, metrics: m}
+}
+
+type instrumented struct {
+	router  Router
+	metrics *Metrics
+}
+
+func (i *instrumented) Route(req *http.Request) http.Handler {
+	h := i.router.Route(req)
+	if h == nil {
+		return nil
+	}
+	pattern := Unpatterned
+	if route := Matched(req); route != nil {
+		pattern = route.Pattern
+	}
+	return i.observe(h, pattern)
+}
+
+func (i *instrumented) unwrap() Router              { return i.router }
+func (i *instrumented) rewrap(router Router) Router { return i.metrics.Instrument(router) }
+func (i *instrumented) String() string              { return "Metrics" }
+
+func (i *instrumented) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h := i.Route(req); h != nil {
+		h.ServeHTTP(w, req)
+	} else {
+		i.observe(http.NotFoundHandler(), Unmatched).ServeHTTP(w, req)
+	}
+}
+
+func (i *instrumented) observe(h http.Handler, pattern string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		method := req.Method
+		if !metricMethods[method] {
+			method = OtherMethod // client controlled, keep series bounded
+		}
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w}
+		h.ServeHTTP(sw, req)
+		i.metrics.observe(method, pattern, sw.Status(), time.Since(start))
+	})
+}
+
+func (m *Metrics) observe(method, pattern string, status int, d time.Duration) {
+	seconds := d.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.requests == nil {
+		m.requests = make(map[requestLabels]uint64)
+		m.latencies = make(map[latencyLabels]*histogram)
+		m.bounds = append([]float64(nil), m.buckets()...)
+	}
+	buckets := m.bounds
+
+	m.requests[requestLabels{method, pattern, status}]++
+
+	hist := m.latencies[latencyLabels{method, pattern}]
+	if hist == nil {
+		hist = &histogram{counts: make([]uint64, len(buckets))}
+		m.latencies[latencyLabels{method, pattern}] = hist
+	}
+	for i, le := range buckets {
+		if seconds <= le {
+			hist.counts[i]++
+		}
+	}
+	hist.count++
+	hist.sum += seconds
+}
+
+func (m *Metrics) buckets() []float64 {
+	if m.Buckets != nil {
+		return m.Buckets
+	}
+	return DefaultBuckets
+}
+
+// ServeHTTP writes collected metrics in the Prometheus text format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes collected metrics to w in the Prometheus text format.
+// Metrics are copied before writing, so slow writers do not block
+// recording of served requests.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	type requestSeries struct {
+		requestLabels
+		count uint64
+	}
+	type latencySeries struct {
+		latencyLabels
+		histogram
+	}
+
+	m.mu.Lock()
+	buckets := m.bounds
+	requests := make([]requestSeries, 0, len(m.requests))
+	for l, count := range m.requests {
+		requests = append(requests, requestSeries{l, count})
+	}
+	latencies := make([]latencySeries, 0, len(m.latencies))
+	for l, hist := range m.latencies {
+		h := *hist
+		h.counts = append([]uint64(nil), hist.counts...)
+		latencies = append(latencies, latencySeries{l, h})
+	}
+	m.mu.Unlock()
+
+	sort.Slice(requests, func(i, j int) bool {
+		a, b := requests[i], requests[j]
+		if a.pattern != b.pattern {
+			return a.pattern < b.pattern
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.status < b.status
+	})
+	sort.Slice(latencies, func(i, j int) bool {
+		a, b := latencies[i], latencies[j]
+		if a.pattern != b.pattern {
+			return a.pattern < b.pattern
+		}
+		return a.method < b.method
+	})
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	fmt.Fprintln(bw, "# HELP http_requests_total Total number of HTTP requests by method, route pattern and status.")
+	fmt.Fprintln(bw, "# TYPE http_requests_total counter")
+	for _, r := range requests {
+		fmt.Fprintf(bw, "http_requests_total{method=\"%s\",pattern=\"%s\",status=\"%d\"} %d\n",
+			escapeLabel(r.method), escapeLabel(r.pattern), r.status, r.count)
+	}
+
+	fmt.Fprintln(bw, "# HELP http_request_duration_seconds HTTP request latency by method and route pattern.")
+	fmt.Fprintln(bw, "# TYPE http_request_duration_seconds histogram")
+	for _, l := range latencies {
+		labels := fmt.Sprintf("method=\"%s\",pattern=\"%s\"", escapeLabel(l.method), escapeLabel(l.pattern))
+		for i, le := range buckets {
+			fmt.Fprintf(bw, "http_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n",
+				labels, strconv.FormatFloat(le, 'g', -1, 64), l.counts[i])
+		}
+		fmt.Fprintf(bw, "http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, l.count)
+		fmt.Fprintf(bw, "http_request_duration_seconds_sum{%s} %s\n", labels, strconv.FormatFloat(l.sum, 'g', -1, 64))
+		fmt.Fprintf(bw, "http_request_duration_seconds_count{%s} %d\n", labels, l.count)
+	}
+
+	err := bw.Flush()
+	return cw.n, err
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// statusWriter records the response status and size
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+// Status returns the response status, which is
+// http.StatusOK if the handler has not written one.
+func (w *statusWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Flush sends buffered data to the client
+// if the underlying writer supports it.
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap allows http.ResponseController to reach
+// the underlying http.ResponseWriter.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,164 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestMetricsByRoutePattern(t *testing.T) {
+	t.Parallel()
+	metrics := &fastroute.Metrics{Buckets: []float64{1, 60}}
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		if fastroute.Parameters(req).ByName("id") == "0" {
+			w.WriteHeader(http.StatusGone)
+		}
+	}
+
+	router := metrics.Instrument(fastroute.Chain(
+		fastroute.New("/status", handler),
+		fastroute.New("/users/:id", handler),
+	))
+
+	for _, path := range []string{"/status", "/users/1", "/users/2", "/users/0", "/users/\"x\"/a"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	w := httptest.NewRecorder()
+	metrics.ServeHTTP(w, nil)
+	out := w.Body.String()
+
+	expected := []string{
+		`http_requests_total{method="GET",pattern="/status",status="200"} 1`,
+		`http_requests_total{method="GET",pattern="/users/:id",status="200"} 2`,
+		`http_requests_total{method="GET",pattern="/users/:id",status="410"} 1`,
+		`http_requests_total{method="GET",pattern="unmatched",status="404"} 1`,
+		`http_request_duration_seconds_bucket{method="GET",pattern="/users/:id",le="60"} 3`,
+		`http_request_duration_seconds_bucket{method="GET",pattern="/users/:id",le="+Inf"} 3`,
+		`http_request_duration_seconds_count{method="GET",pattern="/status"} 1`,
+		"# TYPE http_request_duration_seconds histogram",
+	}
+	for _, line := range expected {
+		if !strings.Contains(out, line+"\n") {
+			t.Fatalf("expected metrics to contain: %s, but got:\n%s", line, out)
+		}
+	}
+}
+
+func TestMetricsInstrumentedRouterInChain(t *testing.T) {
+	t.Parallel()
+	metrics := &fastroute.Metrics{}
+	router := fastroute.Chain(
+		metrics.Instrument(fastroute.New("/a", http.NotFoundHandler())),
+		fastroute.New("/b", func(w http.ResponseWriter, req *http.Request) {}),
+	)
+
+	req, _ := http.NewRequest("GET", "/b", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected chain to continue past instrumented router, but got: %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	metrics.ServeHTTP(w, nil)
+	if strings.Contains(w.Body.String(), "http_requests_total{") {
+		t.Fatalf("did not expect any requests recorded, but got:\n%s", w.Body.String())
+	}
+}
+
+func TestMetricsLabelsRoutersWithoutPatterns(t *testing.T) {
+	t.Parallel()
+	metrics := &fastroute.Metrics{Buckets: []float64{1}}
+	router := metrics.Instrument(fastroute.RouterFunc(func(req *http.Request) http.Handler {
+		if strings.HasPrefix(req.URL.Path, "/u/") {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {})
+		}
+		return nil
+	}))
+
+	for _, path := range []string{"/u/0", "/u/1", "/u/2"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	for _, method := range []string{"FOO", "BAR"} {
+		req, _ := http.NewRequest(method, "/u/9", nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// buckets changed after requests were recorded are not used
+	metrics.Buckets = []float64{1, 2, 3}
+	req, _ := http.NewRequest("GET", "/u/3", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	w := httptest.NewRecorder()
+	metrics.ServeHTTP(w, nil)
+	out := w.Body.String()
+	if strings.Count(out, "http_requests_total{") != 2 || !strings.Contains(out, `http_requests_total{method="GET",pattern="unpatterned",status="200"} 4`+"\n") {
+		t.Fatalf("expected requests to be recorded in a single series, but got:\n%s", out)
+	}
+	if !strings.Contains(out, `http_requests_total{method="OTHER",pattern="unpatterned",status="200"} 2`+"\n") {
+		t.Fatalf("expected non standard methods to be recorded in a single series, but got:\n%s", out)
+	}
+	if strings.Contains(out, `le="2"`) {
+		t.Fatalf("expected buckets to remain the same, but got:\n%s", out)
+	}
+}
+
+type blockingWriter struct {
+	once    sync.Once
+	writing chan struct{}
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.writing) })
+	<-w.release
+	return len(p), nil
+}
+
+func TestMetricsSlowScrapeDoesNotBlockRequests(t *testing.T) {
+	t.Parallel()
+	metrics := &fastroute.Metrics{}
+	var routes []fastroute.Router
+	for i := 0; i < 10; i++ {
+		routes = append(routes, fastroute.New(fmt.Sprintf("/r%d/:id", i), func(w http.ResponseWriter, req *http.Request) {}))
+	}
+	router := metrics.Instrument(fastroute.Chain(routes...))
+
+	// more series than fit in a write buffer
+	for i := 0; i < 10; i++ {
+		req, _ := http.NewRequest("GET", fmt.Sprintf("/r%d/1", i), nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	w := &blockingWriter{writing: make(chan struct{}), release: make(chan struct{})}
+	done := make(chan struct{})
+	go func() {
+		metrics.WriteTo(w)
+		close(done)
+	}()
+	<-w.writing
+
+	served := make(chan struct{})
+	go func() {
+		req, _ := http.NewRequest("GET", "/r0/2", nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		close(served)
+	}()
+	select {
+	case <-served:
+	case <-time.After(time.Second):
+		t.Fatal("expected request to be recorded while metrics are being written")
+	}
+	close(w.release)
+	<-done
+}
@@ -0,0 +1,109 @@
+package fastroute
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Offer binds the handler to the media type it produces.
+type Offer struct {
+	MediaType string // like "application/json"
+	Handler   http.Handler
+}
+
+// Negotiate creates Router selecting the handler by the
+// request Accept header, using quality values and wildcards.
+// When the client accepts offers equally, the first of them
+// is preferred, including requests without Accept header.
+// If none of the offers is acceptable, the request is
+// responded with 406 Not Acceptable.
+//
+// It is meant to be composed under New, so the path
+// parameters are available to the negotiated handler:
+//
+//	fastroute.New("/users/:id", fastroute.Negotiate(
+//		fastroute.Offer{MediaType: "application/json", Handler: userJSON},
+//		fastroute.Offer{MediaType: "text/html", Handler: userHTML},
+//	))
+func Negotiate(offers ...Offer) Router {
+	return negotiation(offers)
+}
+
+type negotiation []Offer
+
+func (n negotiation) Route(req *http.Request) http.Handler {
+	accept := req.Header.Get("Accept")
+	best, bestQ := -1, 0.0
+	for i, offer := range n {
+		if q := quality(accept, offer.MediaType); q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+
+	if best == -1 {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Add("Vary", "Accept")
+			http.Error(w, http.StatusText(http.StatusNotAcceptable), http.StatusNotAcceptable)
+		})
+	}
+
+	offer := n[best]
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		w.Header().Set("Content-Type", offer.MediaType)
+		offer.Handler.ServeHTTP(w, req)
+	})
+}
+
+func (n negotiation) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	n.Route(req).ServeHTTP(w, req)
+}
+
+// resolves quality value of the media type by the most
+// specific matching media range of Accept header
+func quality(accept, mediaType string) float64 {
+	if strings.TrimSpace(accept) == "" {
+		return 1
+	}
+	mediaType = strings.ToLower(mediaType)
+	slash := strings.IndexByte(mediaType, '/')
+
+	q, specificity := 0.0, -1
+	for _, mr := range strings.Split(accept, ",") {
+		params := strings.Split(mr, ";")
+		rng := strings.ToLower(strings.TrimSpace(params[0]))
+
+		var s int
+		switch {
+		case rng == mediaType:
+			s = 2
+		case slash != -1 && rng == mediaType[:slash]+"/*":
+			s = 1
+		case rng == "*/*":
+			s = 0
+		default:
+			continue
+		}
+		if s <= specificity {
+			continue
+		}
+
+		specificity, q = s, qvalue(params[1:])
+	}
+	return q
+}
+
+// resolves quality value of the media range parameters, 1 by default
+func qvalue(params []string) float64 {
+	q := 1.0
+	for _, p := range params {
+		kv := strings.SplitN(strings.TrimSpace(p), "=", 2)
+		if len(kv) == 2 && strings.ToLower(kv[0]) == "q" {
+			if v, err := strconv.ParseFloat(kv[1], 64); err == nil {
+				q = v
+			}
+		}
+	}
+	return q
+}
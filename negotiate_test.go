@@ -0,0 +1,52 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestNegotiateByAccept(t *testing.T) {
+	t.Parallel()
+	handler := func(format string) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprintf(w, "%s:%s", format, fastroute.Parameters(req).ByName("id"))
+		}
+	}
+	router := fastroute.New("/users/:id", fastroute.Negotiate(
+		fastroute.Offer{MediaType: "application/json", Handler: handler("json")},
+		fastroute.Offer{MediaType: "text/html", Handler: handler("html")},
+		fastroute.Offer{MediaType: "text/csv", Handler: handler("csv")},
+	))
+
+	cases := []struct {
+		accept, contentType string
+		code                int
+		body                string
+	}{
+		{"", "application/json", 200, "json:1"},
+		{"*/*", "application/json", 200, "json:1"},
+		{"text/html", "text/html", 200, "html:1"},
+		{"text/*", "text/html", 200, "html:1"},
+		{"text/*;q=0.5, text/csv", "text/csv", 200, "csv:1"},
+		{"application/json;q=0.2, text/html;q=0.8", "text/html", 200, "html:1"},
+		{"*/*;q=0.1, application/json;q=0", "text/html", 200, "html:1"},
+		{"image/png", "text/plain; charset=utf-8", 406, "Not Acceptable\n"},
+	}
+
+	for _, c := range cases {
+		req, _ := http.NewRequest("GET", "/users/1", nil)
+		req.Header.Set("Accept", c.accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != c.code || w.Body.String() != c.body || w.Header().Get("Content-Type") != c.contentType {
+			t.Fatalf("unexpected response for accept %q: %d %q %s", c.accept, w.Code, w.Body.String(), w.Header().Get("Content-Type"))
+		}
+		if fastroute.Parameters(req) != nil {
+			t.Fatal("expected parameters to be recycled")
+		}
+	}
+}
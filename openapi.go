@@ -0,0 +1,249 @@
+package fastroute
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// OpenAPI metadata key describes the route operation in
+// generated OpenAPI document. Path parameters are generated
+// from the route pattern and operation id defaults to the
+// route name.
+var OpenAPI = NewKey[OpenAPIOperation]("openapi")
+
+// OpenAPIDocument is OpenAPI 3 document subset,
+// generated from the routes by GenerateOpenAPI.
+type OpenAPIDocument struct {
+	OpenAPI string                     `json:"openapi"`
+	Info    OpenAPIInfo                `json:"info"`
+	Paths   map[string]OpenAPIPathItem `json:"paths"`
+}
+
+// OpenAPIInfo is the metadata about the API.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIPathItem maps lowercase request methods to operations.
+type OpenAPIPathItem map[string]*OpenAPIOperation
+
+// OpenAPIOperation describes a single API operation on a path.
+type OpenAPIOperation struct {
+	OperationID string                     `json:"operationId,omitempty"`
+	Summary     string                     `json:"summary,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Deprecated  bool                       `json:"deprecated,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses,omitempty"`
+}
+
+// OpenAPIParameter describes a single operation parameter.
+type OpenAPIParameter struct {
+	Name        string        `json:"name"`
+	In          string        `json:"in"`
+	Required    bool          `json:"required,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Schema      OpenAPISchema `json:"schema,omitempty"`
+	CatchAll    bool          `json:"x-fastroute-catch-all,omitempty"` // path parameter matches the rest of the path
+}
+
+// OpenAPIRequestBody describes the operation request body.
+type OpenAPIRequestBody struct {
+	Description string                      `json:"description,omitempty"`
+	Required    bool                        `json:"required,omitempty"`
+	Content     map[string]OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse describes a single operation response.
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType describes the content by media type.
+type OpenAPIMediaType struct {
+	Schema OpenAPISchema `json:"schema,omitempty"`
+}
+
+// OpenAPISchema is JSON schema object.
+type OpenAPISchema map[string]interface{}
+
+// GenerateOpenAPI walks the routes composed into Methods and
+// generates OpenAPI 3 document. Route patterns are converted to
+// path templates, named parameter ":id" becomes "{id}" and
+// catch-all parameter "*path" becomes "{path}", which is
+// documented to match the rest of the path and marked with
+// the "x-fastroute-catch-all" extension, so it is restored
+// on import.
+//
+// Routes which are not composed into Methods are skipped, since
+// the request method is not known. If the same path and method
+// is registered more than once, only the first route is used,
+// since others are shadowed.
+func GenerateOpenAPI(router Router, info OpenAPIInfo) *OpenAPIDocument {
+	doc := &OpenAPIDocument{OpenAPI: "3.0.3", Info: info, Paths: make(map[string]OpenAPIPathItem)}
+	Walk(router, func(method string, route *Route) error {
+		if method == "" {
+			return nil
+		}
+
+		path, params := openAPIPath(route.Pattern)
+		item := doc.Paths[path]
+		if item == nil {
+			item = make(OpenAPIPathItem)
+			doc.Paths[path] = item
+		}
+		method = strings.ToLower(method)
+		if _, shadowed := item[method]; shadowed {
+			return nil
+		}
+
+		op, _ := OpenAPI.Of(route)
+		if op.OperationID == "" {
+			op.OperationID = route.Name
+		}
+		op.Parameters = append(undeclared(params, op.Parameters), op.Parameters...)
+		if len(op.Responses) == 0 {
+			op.Responses = map[string]OpenAPIResponse{"default": {Description: "default response"}}
+		}
+		item[method] = &op
+		return nil
+	})
+	return doc
+}
+
+// converts route pattern to path template and path parameters
+func openAPIPath(pattern string) (string, []OpenAPIParameter) {
+	var params []OpenAPIParameter
+	segments := strings.Split(pattern, "/")
+	for i, seg := range segments {
+		if seg == "" || (seg[0] != ':' && seg[0] != '*') {
+			continue
+		}
+		param := OpenAPIParameter{
+			Name:     seg[1:],
+			In:       "path",
+			Required: true,
+			Schema:   OpenAPISchema{"type": "string"},
+		}
+		if seg[0] == '*' {
+			param.Description = "matches the rest of the path"
+			param.CatchAll = true
+		}
+		params = append(params, param)
+		segments[i] = "{" + seg[1:] + "}"
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// filters out generated path parameters, which are already declared
+func undeclared(params, declared []OpenAPIParameter) []OpenAPIParameter {
+	var left []OpenAPIParameter
+next:
+	for _, p := range params {
+		for _, d := range declared {
+			if d.In == p.In && d.Name == p.Name {
+				continue next
+			}
+		}
+		left = append(left, p)
+	}
+	return left
+}
+
+// WriteJSON writes indented JSON document to w.
+func (d *OpenAPIDocument) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// WriteYAML writes YAML document to w.
+func (d *OpenAPIDocument) WriteYAML(w io.Writer) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	writeYAML(&buf, v, 0)
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// writes decoded JSON value as YAML block, scalars are
+// written as JSON, which is a valid YAML flow scalar
+func writeYAML(buf *bytes.Buffer, v interface{}, indent int) {
+	pad := strings.Repeat("  ", indent)
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(buf, "%s%s:", pad, yamlScalar(k))
+			writeYAMLValue(buf, t[k], indent+1)
+		}
+	case []interface{}:
+		for _, item := range t {
+			if m, ok := item.(map[string]interface{}); ok && len(m) > 0 {
+				// compact form, where the first key follows the dash
+				var sub bytes.Buffer
+				writeYAML(&sub, m, indent+1)
+				buf.WriteString(pad + "- ")
+				buf.Write(sub.Bytes()[len(pad)+2:])
+				continue
+			}
+			fmt.Fprintf(buf, "%s-", pad)
+			writeYAMLValue(buf, item, indent+1)
+		}
+	}
+}
+
+func writeYAMLValue(buf *bytes.Buffer, v interface{}, indent int) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if len(t) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+		buf.WriteByte('\n')
+		writeYAML(buf, t, indent)
+	case []interface{}:
+		if len(t) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+		buf.WriteByte('\n')
+		writeYAML(buf, t, indent)
+	default:
+		fmt.Fprintf(buf, " %s\n", yamlScalar(t))
+	}
+}
+
+func yamlScalar(v interface{}) string {
+	if s, ok := v.(string); ok && s != "" && strings.IndexAny(s, ":#{}[],&*!|>'\"%@`\n\\") == -1 &&
+		strings.TrimSpace(s) == s && !strings.ContainsAny(s[:1], "-?0123456789") && !yamlReserved[strings.ToLower(s)] {
+		return s
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+var yamlReserved = map[string]bool{"true": true, "false": true, "null": true, "yes": true, "no": true, "on": true, "off": true, "~": true}
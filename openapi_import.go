@@ -0,0 +1,185 @@
+package fastroute
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var openAPIMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+// UnmarshalJSON decodes operations of the path item,
+// ignoring other path item fields, like summary or servers.
+func (p *OpenAPIPathItem) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*p = make(OpenAPIPathItem)
+	for key, raw := range fields {
+		if !openAPIMethods[key] {
+			continue
+		}
+		op := &OpenAPIOperation{}
+		if err := json.Unmarshal(raw, op); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		(*p)[key] = op
+	}
+	return nil
+}
+
+// ImportOpenAPI decodes OpenAPI 3 JSON document from r
+// and compiles its paths to the Router, see Compile.
+func ImportOpenAPI(r io.Reader, handlers map[string]http.Handler) (Router, error) {
+	var doc OpenAPIDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc.Compile(handlers)
+}
+
+// Compile builds the Router from the document paths, for
+// contract first services. Path templates are converted to
+// route patterns, "{id}" becomes ":id", or "*id" if any operation
+// marks the path parameter as catch-all with "x-fastroute-catch-all"
+// extension, as GenerateOpenAPI does, and operations are
+// bound to the handlers registered by operation id. Routes
+// are named by operation id and the operation is attached
+// as OpenAPI metadata.
+//
+// The compiled router responds with 501 Not Implemented for
+// operations without a registered handler and 405 Method
+// Not Allowed if the path is known, but the method is not.
+// Concrete paths are matched before templated ones.
+func (d *OpenAPIDocument) Compile(handlers map[string]http.Handler) (Router, error) {
+	type entry struct {
+		segments []string
+		pattern  string
+		item     OpenAPIPathItem
+	}
+	var entries []entry
+	for path, item := range d.Paths {
+		pattern, err := openAPIPattern(path, catchAlls(item))
+		if err != nil {
+			return nil, fmt.Errorf(`paths["%s"]: %w`, path, err)
+		}
+		entries = append(entries, entry{strings.Split(pattern, "/"), pattern, item})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return lessConcrete(entries[i].segments, entries[j].segments)
+	})
+
+	chains := make(map[string][]Router)
+	for _, e := range entries {
+		methods := make([]string, 0, len(e.item))
+		for method := range e.item {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			op := e.item[method]
+			h, ok := handlers[op.OperationID]
+			if !ok || h == nil {
+				h = http.HandlerFunc(notImplemented)
+			}
+			route, err := compile(op.OperationID, e.pattern, h)
+			if err != nil {
+				return nil, fmt.Errorf(`paths["%s"].%s: %w`, e.pattern, method, err)
+			}
+			method = strings.ToUpper(method)
+			chains[method] = append(chains[method], With(route, OpenAPI.Value(*op)))
+		}
+	}
+
+	routes := make(Methods, len(chains))
+	for method, chain := range chains {
+		routes[method] = Chain(chain...)
+	}
+	return Chain(routes, methodNotAllowed(routes)), nil
+}
+
+// path parameters marked as catch-all by any operation
+func catchAlls(item OpenAPIPathItem) map[string]bool {
+	names := make(map[string]bool)
+	for _, op := range item {
+		for _, p := range op.Parameters {
+			if p.In == "path" && p.CatchAll {
+				names[p.Name] = true
+			}
+		}
+	}
+	return names
+}
+
+// converts OpenAPI path template to route pattern
+func openAPIPattern(path string, catchAll map[string]bool) (string, error) {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		open, close := strings.IndexByte(seg, '{'), strings.LastIndexByte(seg, '}')
+		switch {
+		case open == -1 && close == -1:
+			if strings.IndexAny(seg, ":*") != -1 {
+				return "", fmt.Errorf("static segment cannot contain param matching signs: %s", seg)
+			}
+		case open == 0 && close == len(seg)-1 && len(seg) > 2 && strings.IndexAny(seg[1:close], "{}") == -1:
+			sign, name := ":", seg[1:close]
+			if catchAll[name] {
+				sign = "*"
+			}
+			segments[i] = sign + name
+		default:
+			return "", fmt.Errorf("only whole segment path templates are supported: %s", seg)
+		}
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// whether path segments a should be matched before b, static
+// segments take precedence over parameters and catch-alls
+func lessConcrete(a, b []string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if ra, rb := segmentRank(a[i]), segmentRank(b[i]); ra != rb {
+			return ra < rb
+		}
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func notImplemented(w http.ResponseWriter, req *http.Request) {
+	http.Error(w, http.StatusText(http.StatusNotImplemented), http.StatusNotImplemented)
+}
+
+// responds with 405 if the path is routed by other methods
+func methodNotAllowed(routes Methods) Router {
+	return RouterFunc(func(req *http.Request) http.Handler {
+		allows := routes.Allowed(req)
+		if len(allows) == 0 {
+			return nil
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Allow", strings.Join(allows, ","))
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		})
+	})
+}
+
+func segmentRank(seg string) int {
+	switch {
+	case strings.HasPrefix(seg, ":"):
+		return 1
+	case strings.HasPrefix(seg, "*"):
+		return 2
+	}
+	return 0
+}
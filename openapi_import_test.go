@@ -0,0 +1,115 @@
+package fastroute_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestImportOpenAPI(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "%s %s %v", fastroute.Matched(req).Name, fastroute.Pattern(req), fastroute.Parameters(req))
+	}
+	handlers := map[string]http.Handler{
+		"getUser": http.HandlerFunc(handler),
+		"getMe":   http.HandlerFunc(handler),
+	}
+
+	router, err := fastroute.ImportOpenAPI(strings.NewReader(`{
+		"openapi": "3.0.3",
+		"info": {"title": "Users", "version": "1"},
+		"paths": {
+			"/users/{id}": {
+				"summary": "user",
+				"parameters": [{"name": "id", "in": "path", "required": true}],
+				"get": {"operationId": "getUser"},
+				"delete": {"operationId": "deleteUser"}
+			},
+			"/users/me": {
+				"get": {"operationId": "getMe"}
+			}
+		}
+	}`), handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		method, path string
+		code         int
+		body, allow  string
+	}{
+		{"GET", "/users/5", 200, "getUser /users/:id [{id 5}]", ""},
+		{"GET", "/users/me", 200, "getMe /users/me []", ""},
+		{"DELETE", "/users/5", 501, "Not Implemented\n", ""},
+		{"POST", "/users/5", 405, "Method Not Allowed\n", "DELETE,GET"},
+		{"GET", "/unknown", 404, "404 page not found\n", ""},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, c.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != c.code || w.Body.String() != c.body || w.Header().Get("Allow") != c.allow {
+			t.Fatalf("unexpected response for %s %s: %d %q allow: %q", c.method, c.path, w.Code, w.Body.String(), w.Header().Get("Allow"))
+		}
+	}
+
+	// imported operations are documented the same way
+	doc := fastroute.GenerateOpenAPI(router, fastroute.OpenAPIInfo{})
+	if op := doc.Paths["/users/{id}"]["get"]; op == nil || op.OperationID != "getUser" || len(op.Parameters) != 1 {
+		t.Fatalf("unexpected generated operation: %+v", op)
+	}
+}
+
+func TestImportOpenAPIRestoresCatchAll(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "%s %v", fastroute.Pattern(req), fastroute.Parameters(req))
+	}
+	generated := fastroute.GenerateOpenAPI(fastroute.Methods{
+		"GET": fastroute.Chain(
+			fastroute.Named("getFile", "/files/*path", handler),
+			fastroute.Named("getReadme", "/files/readme", handler),
+		),
+	}, fastroute.OpenAPIInfo{Title: "Files", Version: "1"})
+
+	var buf bytes.Buffer
+	if err := generated.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	router, err := fastroute.ImportOpenAPI(&buf, map[string]http.Handler{
+		"getFile":   http.HandlerFunc(handler),
+		"getReadme": http.HandlerFunc(handler),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		"/files/a/b.txt": "/files/*path [{path /a/b.txt}]",
+		"/files/readme":  "/files/readme []",
+	}
+	for path, expected := range cases {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != 200 || w.Body.String() != expected {
+			t.Fatalf("unexpected response for %s: %d %q", path, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestImportOpenAPIUnsupportedTemplate(t *testing.T) {
+	t.Parallel()
+	_, err := fastroute.ImportOpenAPI(strings.NewReader(`{"paths": {"/files/{name}.json": {"get": {}}}}`), nil)
+	expected := `paths["/files/{name}.json"]: only whole segment path templates are supported: {name}.json`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
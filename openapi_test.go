@@ -0,0 +1,121 @@
+package fastroute_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestGenerateOpenAPI(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	router := fastroute.Methods{
+		"GET": fastroute.Chain(
+			fastroute.With(fastroute.Named("getUser", "/users/:id", handler), fastroute.OpenAPI.Value(fastroute.OpenAPIOperation{
+				Summary: "Get user",
+				Responses: map[string]fastroute.OpenAPIResponse{
+					"200": {Description: "user", Content: map[string]fastroute.OpenAPIMediaType{
+						"application/json": {Schema: fastroute.OpenAPISchema{"type": "object"}},
+					}},
+				},
+			})),
+			fastroute.New("/users/:uid", handler), // shadowed
+			fastroute.New("/files/*path", handler),
+		),
+		"DELETE": fastroute.Named("deleteUser", "/users/:id", handler),
+	}
+
+	doc := fastroute.GenerateOpenAPI(router, fastroute.OpenAPIInfo{Title: "Users", Version: "1.0"})
+
+	var buf bytes.Buffer
+	if err := doc.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var actual map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &actual); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected map[string]interface{}
+	json.Unmarshal([]byte(`{
+		"openapi": "3.0.3",
+		"info": {"title": "Users", "version": "1.0"},
+		"paths": {
+			"/users/{id}": {
+				"get": {
+					"operationId": "getUser",
+					"summary": "Get user",
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {"200": {"description": "user", "content": {"application/json": {"schema": {"type": "object"}}}}}
+				},
+				"delete": {
+					"operationId": "deleteUser",
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {"default": {"description": "default response"}}
+				}
+			},
+			"/users/{uid}": {
+				"get": {
+					"parameters": [{"name": "uid", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {"default": {"description": "default response"}}
+				}
+			},
+			"/files/{path}": {
+				"get": {
+					"parameters": [{"name": "path", "in": "path", "required": true, "description": "matches the rest of the path", "schema": {"type": "string"}, "x-fastroute-catch-all": true}],
+					"responses": {"default": {"description": "default response"}}
+				}
+			}
+		}
+	}`), &expected)
+
+	a, _ := json.Marshal(actual)
+	e, _ := json.Marshal(expected)
+	if !bytes.Equal(a, e) {
+		t.Fatalf("unexpected document:\n%s\nexpected:\n%s", a, e)
+	}
+}
+
+func TestOpenAPIDocumentYAML(t *testing.T) {
+	t.Parallel()
+	router := fastroute.Methods{
+		"GET": fastroute.With(fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {}),
+			fastroute.OpenAPI.Value(fastroute.OpenAPIOperation{Summary: "Get: user", Tags: []string{"users"}})),
+	}
+
+	var buf bytes.Buffer
+	if err := fastroute.GenerateOpenAPI(router, fastroute.OpenAPIInfo{Title: "API", Version: "1"}).WriteYAML(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"info:",
+		"  title: API",
+		`  version: "1"`,
+		`openapi: "3.0.3"`,
+		"paths:",
+		`  "/users/{id}":`,
+		"    get:",
+		"      parameters:",
+		"        - in: path",
+		"          name: id",
+		"          required: true",
+		"          schema:",
+		"            type: string",
+		"      responses:",
+		"        default:",
+		"          description: default response",
+		`      summary: "Get: user"`,
+		"      tags:",
+		"        - users",
+		"",
+	}, "\n")
+	if buf.String() != expected {
+		t.Fatalf("unexpected yaml:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
@@ -0,0 +1,193 @@
+package fastroute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+)
+
+// Proxy creates handler forwarding requests to the upstream
+// target URL. The target path may reference route parameters
+// by ":name" or "*name", which are substituted with the values
+// captured by the route pattern:
+//
+//	fastroute.New("/billing/:account/*rest", fastroute.Proxy("http://billing/:account*rest"))
+//
+// Proxies "/billing/42/invoices?page=2" to "http://billing/42/invoices?page=2".
+// Query string of the request is preserved and appended to the
+// query of the target, if any. X-Forwarded-For, X-Forwarded-Host
+// and X-Forwarded-Proto headers are set. Panics if target is not
+// an absolute URL.
+//
+// Requests with parameter values having "." or ".." path segments
+// are rejected with 400 Bad Request, so the upstream path cannot
+// escape the target. Upstream errors are responded with 502 Bad
+// Gateway and logged, see ProxyOptions, or 413 Request Entity
+// Too Large if the request body was limited by Bound.
+func Proxy(target string) http.Handler {
+	return ProxyOptions{}.Handler(target)
+}
+
+// ProxyOptions configure handlers created by Proxy.
+type ProxyOptions struct {
+	// ErrorLog is called with upstream errors and target
+	// parameters, which are not captured by the route. If
+	// nil, errors are logged by the standard logger along
+	// with the route pattern.
+	ErrorLog func(*http.Request, error)
+}
+
+// Handler creates the proxy handler, the same as Proxy.
+func (o ProxyOptions) Handler(target string) http.Handler {
+	u, err := url.Parse(target)
+	if err != nil {
+		panic("invalid proxy target: " + err.Error())
+	}
+	if u.Scheme == "" || u.Host == "" {
+		panic("proxy target must be an absolute URL: " + target)
+	}
+
+	p := &proxy{target: u, path: template(u.Path), errorLog: o.ErrorLog}
+	if p.errorLog == nil {
+		p.errorLog = logError
+	}
+	p.upstream = &httputil.ReverseProxy{
+		Rewrite:      p.rewrite,
+		ErrorHandler: p.fail,
+		ErrorLog:     log.New(io.Discard, "", 0), // errors are reported by fail
+	}
+	return p
+}
+
+type proxy struct {
+	errorLog func(*http.Request, error)
+	upstream *httputil.ReverseProxy
+	target   *url.URL
+	path     []string // literal parts and parameter references
+}
+
+// splits target path into literal parts and parameter
+// references, which start with ':' or '*' and are named
+// by the following letters, digits or underscores
+func template(path string) []string {
+	var parts []string
+	for path != "" {
+		i := strings.IndexAny(path, ":*")
+		if i != 0 {
+			if i < 0 {
+				i = len(path)
+			}
+			parts = append(parts, path[:i])
+			path = path[i:]
+			continue
+		}
+		n := 1
+		for n < len(path) && isNameChar(path[n]) {
+			n++
+		}
+		if n == 1 {
+			panic(fmt.Sprintf("proxy target param must be named after sign: %s", path))
+		}
+		parts = append(parts, path[:n])
+		path = path[n:]
+	}
+	return parts
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func (p *proxy) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	params := Parameters(req)
+	for _, part := range p.path {
+		if part[0] != ':' && part[0] != '*' {
+			continue
+		}
+		if !has(params, part[1:]) {
+			p.errorLog(req, fmt.Errorf("proxy target param %s is not captured by route: %s", part, Pattern(req)))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if dotSegments(params.ByName(part[1:])) {
+			http.Error(w, "invalid URL path", http.StatusBadRequest)
+			return
+		}
+	}
+	// upstream errors are reported with the outgoing request,
+	// the incoming one is kept in order to log it instead
+	ctx := context.WithValue(req.Context(), proxyKey{}, req)
+	p.upstream.ServeHTTP(w, req.WithContext(ctx))
+}
+
+type proxyKey struct{}
+
+func (p *proxy) fail(w http.ResponseWriter, req *http.Request, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if in, ok := req.Context().Value(proxyKey{}).(*http.Request); ok {
+		req = in
+	}
+	p.errorLog(req, err)
+	w.WriteHeader(http.StatusBadGateway)
+}
+
+func (p *proxy) rewrite(pr *httputil.ProxyRequest) {
+	params := Parameters(pr.In)
+	if ps, ok := pr.Out.Body.(*parameters); ok {
+		// transport may still read the body after parameters are recycled,
+		// the current one is kept, since it may be limited by Bound
+		pr.Out.Body = ps.ReadCloser
+	}
+
+	var path strings.Builder
+	for _, part := range p.path {
+		switch part[0] {
+		case ':', '*':
+			path.WriteString(params.ByName(part[1:]))
+		default:
+			path.WriteString(part)
+		}
+	}
+
+	out := pr.Out.URL
+	out.Scheme, out.Host = p.target.Scheme, p.target.Host
+	out.Path, out.RawPath = path.String(), ""
+	switch {
+	case p.target.RawQuery == "":
+	case out.RawQuery == "":
+		out.RawQuery = p.target.RawQuery
+	default:
+		out.RawQuery = p.target.RawQuery + "&" + out.RawQuery
+	}
+	pr.Out.Host = ""
+	pr.SetXForwarded()
+}
+
+// whether the path has "." or ".." segments
+func dotSegments(path string) bool {
+	for _, seg := range strings.Split(path, "/") {
+		if seg == "." || seg == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+func has(params Params, name string) bool {
+	for _, p := range params {
+		if p.Key == name {
+			return true
+		}
+	}
+	return false
+}
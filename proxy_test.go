@@ -0,0 +1,152 @@
+package fastroute_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestProxyParamSubstitution(t *testing.T) {
+	t.Parallel()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		fmt.Fprintf(w, "%s %s host=%s xff=%s xfh=%s xfp=%s body=%s",
+			req.Method,
+			req.URL.RequestURI(),
+			req.Host,
+			req.Header.Get("X-Forwarded-For"),
+			req.Header.Get("X-Forwarded-Host"),
+			req.Header.Get("X-Forwarded-Proto"),
+			body,
+		)
+	}))
+	defer upstream.Close()
+
+	router := fastroute.Chain(
+		fastroute.New("/billing/:account/*rest", fastroute.Proxy(upstream.URL+"/:account*rest")),
+		fastroute.New("/reports/:id", fastroute.Proxy(upstream.URL+"/v2/reports/:id.pdf?format=a4")),
+	)
+
+	cases := []struct {
+		method, path, body, expected string
+	}{
+		{
+			"GET", "/billing/42/invoices/7?page=2", "",
+			"GET /42/invoices/7?page=2 host=%s xff=192.0.2.1 xfh=example.com xfp=http body=",
+		},
+		{
+			"POST", "/billing/42/", "amount=5", "POST /42/ host=%s xff=192.0.2.1 xfh=example.com xfp=http body=amount=5",
+		},
+		{
+			"GET", "/billing/a%20b/x", "", "GET /a%%20b/x host=%s xff=192.0.2.1 xfh=example.com xfp=http body=",
+		},
+		{
+			"GET", "/reports/5?lang=lt", "",
+			"GET /v2/reports/5.pdf?format=a4&lang=lt host=%s xff=192.0.2.1 xfh=example.com xfp=http body=",
+		},
+	}
+
+	host := strings.TrimPrefix(upstream.URL, "http://")
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.path, strings.NewReader(c.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		expected := fmt.Sprintf(c.expected, host)
+		if w.Code != http.StatusOK || w.Body.String() != expected {
+			t.Fatalf("unexpected response for %s %s: %d %q, expected %q", c.method, c.path, w.Code, w.Body.String(), expected)
+		}
+		if fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters to be recycled for: %s", c.path)
+		}
+	}
+}
+
+func TestProxyErrors(t *testing.T) {
+	t.Parallel()
+	var largest int // largest body received by upstream
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if body, _ := io.ReadAll(req.Body); len(body) > largest {
+			largest = len(body)
+		}
+	}))
+	defer upstream.Close()
+
+	var logs []string
+	opts := fastroute.ProxyOptions{ErrorLog: func(req *http.Request, err error) {
+		logs = append(logs, req.URL.Path+": "+err.Error())
+	}}
+	router := fastroute.Chain(
+		fastroute.Bound(fastroute.New("/up/*rest", opts.Handler(upstream.URL+"/api*rest")), fastroute.Bounds{MaxBodySize: 4}),
+		fastroute.New("/users/:id", opts.Handler(upstream.URL+"/:name")),
+		fastroute.New("/down/:id", opts.Handler("http://127.0.0.1:1/:id")),
+	)
+
+	cases := []struct {
+		method, path, body string
+		code               int
+	}{
+		{"POST", "/up/items", "1234", 200},
+		{"POST", "/up/items", strings.Repeat("x", 100), 413},
+		{"GET", "/up/../../admin", "", 400},
+		{"GET", "/up/a/./b", "", 400},
+		{"GET", "/users/1", "", 500},
+		{"GET", "/down/1", "", 502},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, "/", strings.NewReader(c.body))
+		req.URL.Path = c.path // not cleaned
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != c.code {
+			t.Fatalf("expected status %d for %s %s, but got: %d", c.code, c.method, c.path, w.Code)
+		}
+	}
+
+	upstream.Close() // waits for upstream to read bodies of aborted requests
+	if largest != 4 {
+		t.Fatalf("expected upstream to receive only the limited body, but got %d bytes", largest)
+	}
+	if len(logs) != 2 || logs[0] != "/users/1: proxy target param :name is not captured by route: /users/:id" || !strings.HasPrefix(logs[1], "/down/1: ") {
+		t.Fatalf("unexpected error logs: %q", logs)
+	}
+}
+
+// not parallel, since the standard logger output is replaced
+func TestProxyLogsByStandardLoggerByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	router := fastroute.New("/users/:id", fastroute.Proxy("http://127.0.0.1:1/:name"))
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	expected := "fastroute: GET /users/1 (/users/:id): proxy target param :name is not captured by route: /users/:id\n"
+	if w.Code != http.StatusInternalServerError || !strings.HasSuffix(buf.String(), expected) {
+		t.Fatalf("unexpected response %d and log: %q", w.Code, buf.String())
+	}
+}
+
+func TestProxyInvalidTarget(t *testing.T) {
+	t.Parallel()
+	for _, target := range []string{"/relative/:id", "http://host/:/x", "://bad"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected panic for target: %s", target)
+				}
+			}()
+			fastroute.Proxy(target)
+		}()
+	}
+}
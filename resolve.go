@@ -0,0 +1,122 @@
+package fastroute
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Resolver loads the value of type T, like the tenant from
+// the database, keyed on the path parameter captured by the
+// route, before the handler is served. Resolvers are compared
+// by identity, so they are usually declared as package
+// variables:
+//
+//	var Tenant = &fastroute.Resolver[*Tenant]{Param: "tenant", Load: loadTenant}
+//
+//	router := Tenant.Resolve(fastroute.Chain(
+//		fastroute.New("/tenants/:tenant", showTenant),
+//		fastroute.New("/tenants/:tenant/users", listUsers),
+//	))
+//
+// Handlers get the loaded value by Tenant.Get(req).
+type Resolver[T any] struct {
+	// Param names the path parameter
+	// the value is loaded by.
+	Param string
+
+	// Load loads the value by the parameter value. It may
+	// return ErrNotFound, ErrInvalidParam or other errors
+	// mapped to the status code by StatusOf.
+	Load func(req *http.Request, param string) (T, error)
+
+	// Error optionally writes the response when Load fails,
+	// by default only the status text is written.
+	Error func(w http.ResponseWriter, req *http.Request, err error)
+}
+
+// Resolve wraps router in order to load the value for every
+// request it routes, before the matched handler is served.
+// Requests not routed by New are served as is.
+//
+// Resolve panics if Load is not set, or any route the
+// router is composed of does not capture the Param.
+func (r *Resolver[T]) Resolve(router Router) Router {
+	if r.Load == nil {
+		panic("resolver must have a loader: " + r.Param)
+	}
+	Walk(router, func(method string, route *Route) error {
+		if !captures(route.Pattern, r.Param) {
+			panic(fmt.Sprintf("route %s does not capture resolver param: %s", route.Pattern, r.Param))
+		}
+		return nil
+	})
+	return &resolved[T]{router: router, resolver: r}
+}
+
+// Status returns the response status code for the Load
+// error, the same as StatusOf.
+func (r *Resolver[T]) Status(err error) int {
+	return StatusOf(err)
+}
+
+// Get returns the value loaded for the request, until
+// the request is served or recycled.
+func (r *Resolver[T]) Get(req *http.Request) (T, bool) {
+	v, ok := get(req, r).(T)
+	return v, ok
+}
+
+func (r *Resolver[T]) fail(w http.ResponseWriter, req *http.Request, err error) {
+	if r.Error != nil {
+		r.Error(w, req, err)
+		return
+	}
+	status := StatusOf(err)
+	http.Error(w, http.StatusText(status), status)
+}
+
+// whether the pattern has named or catch-all parameter
+func captures(pattern, param string) bool {
+	for _, seg := range strings.Split(pattern, "/") {
+		if len(seg) > 1 && (seg[0] == ':' || seg[0] == '*') && seg[1:] == param {
+			return true
+		}
+	}
+	return false
+}
+
+type resolved[T any] struct {
+	router   Router
+	resolver *Resolver[T]
+}
+
+func (r *resolved[T]) Route(req *http.Request) http.Handler {
+	h := r.router.Route(req)
+	if h == nil || Matched(req) == nil {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		v, err := r.resolver.Load(req, Parameters(req).ByName(r.resolver.Param))
+		if err != nil {
+			r.resolver.fail(w, req, err)
+			Recycle(req) // matched handler is not served
+			return
+		}
+		set(req, r.resolver, v)
+		h.ServeHTTP(w, req)
+	})
+}
+
+func (r *resolved[T]) unwrap() Router              { return r.router }
+func (r *resolved[T]) rewrap(router Router) Router { return r.resolver.Resolve(router) }
+func (r *resolved[T]) String() string              { return "Resolver(" + r.resolver.Param + ")" }
+
+func (r *resolved[T]) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h := r.Route(req); h != nil {
+		h.ServeHTTP(w, req)
+	} else {
+		http.NotFound(w, req)
+	}
+}
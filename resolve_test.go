@@ -0,0 +1,125 @@
+package fastroute_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+type tenant struct {
+	ID   int
+	Name string
+}
+
+func TestResolverLoadsTypedValue(t *testing.T) {
+	t.Parallel()
+	tenants := map[int]*tenant{1: {1, "acme"}}
+	var loads int
+	Tenant := &fastroute.Resolver[*tenant]{
+		Param: "tenant",
+		Load: func(req *http.Request, param string) (*tenant, error) {
+			loads++
+			id, err := strconv.Atoi(param)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", fastroute.ErrInvalidParam, err)
+			}
+			if t, ok := tenants[id]; ok {
+				return t, nil
+			}
+			return nil, fastroute.ErrNotFound
+		},
+	}
+	Project := &fastroute.Resolver[string]{
+		Param: "project",
+		Load: func(req *http.Request, param string) (string, error) {
+			tenant, _ := Tenant.Get(req)
+			if param == "broken" {
+				return "", errors.New("connection refused")
+			}
+			return tenant.Name + "/" + param, nil
+		},
+	}
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		tenant, _ := Tenant.Get(req)
+		project, _ := Project.Get(req)
+		fmt.Fprint(w, tenant.Name, " ", project)
+	}
+	router := Tenant.Resolve(fastroute.Chain(
+		fastroute.New("/tenants/:tenant", handler),
+		Project.Resolve(fastroute.New("/tenants/:tenant/projects/:project", handler)),
+	))
+
+	cases := []struct {
+		path  string
+		code  int
+		body  string
+		loads int
+	}{
+		{"/tenants/1", 200, "acme ", 1},
+		{"/tenants/1/projects/web", 200, "acme acme/web", 1},
+		{"/tenants/2", 404, "Not Found\n", 1},
+		{"/tenants/x/projects/web", 400, "Bad Request\n", 1},
+		{"/tenants/1/projects/broken", 500, "Internal Server Error\n", 1},
+		{"/users/1", 404, "404 page not found\n", 0},
+	}
+	for _, c := range cases {
+		loads = 0
+		req, _ := http.NewRequest("GET", c.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != c.code || w.Body.String() != c.body || loads != c.loads {
+			t.Fatalf("unexpected response for %s: %d %q, loads: %d", c.path, w.Code, w.Body.String(), loads)
+		}
+		if fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters to be recycled for: %s", c.path)
+		}
+		if _, ok := Tenant.Get(req); ok {
+			t.Fatalf("did not expect resolved value after serving: %s", c.path)
+		}
+	}
+}
+
+func TestResolverCustomError(t *testing.T) {
+	t.Parallel()
+	Item := &fastroute.Resolver[int]{
+		Param: "id",
+		Load: func(req *http.Request, param string) (int, error) {
+			return 0, fastroute.ErrNotFound
+		},
+	}
+	Item.Error = func(w http.ResponseWriter, req *http.Request, err error) {
+		w.WriteHeader(Item.Status(err))
+		fmt.Fprintf(w, "%s: %s %v", fastroute.Pattern(req), fastroute.Parameters(req).ByName("id"), err)
+	}
+
+	router := Item.Resolve(fastroute.New("/items/:id", func(w http.ResponseWriter, req *http.Request) {
+		t.Error("handler should not be served")
+	}))
+	req, _ := http.NewRequest("GET", "/items/5", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != 404 || w.Body.String() != "/items/:id: 5 not found" {
+		t.Fatalf("unexpected response: %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestResolverRequiresParamInRoutes(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if err := recover(); err == nil || !strings.Contains(fmt.Sprint(err), "/users/:id does not capture") {
+			t.Fatalf("unexpected panic: %v", err)
+		}
+	}()
+	r := &fastroute.Resolver[string]{Param: "tenant", Load: func(*http.Request, string) (string, error) { return "", nil }}
+	r.Resolve(fastroute.Chain(
+		fastroute.New("/tenants/:tenant", http.NotFoundHandler()),
+		fastroute.New("/users/:id", http.NotFoundHandler()),
+	))
+}
@@ -94,9 +94,13 @@
 package fastroute
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"math/bits"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -114,15 +118,89 @@ func Parameters(req *http.Request) Params {
 }
 
 // Pattern gives matched route path pattern
-// for this request if it has path parameters.
+// for this request, both for static and dynamic
+// routes.
 //
 // If request parameters were already recycled,
-// or route is static - it will return req.URL.Path.
+// or request was not routed by New - it will
+// return req.URL.Path.
 func Pattern(req *http.Request) string {
 	if p, _ := req.Body.(*parameters); p != nil {
-		return p.pattern
+		return p.route.Pattern
 	}
-	return req.URL.Path // if matched will be same as url path
+	return req.URL.Path
+}
+
+// Route describes the route created by New
+// or Named, which has matched the request.
+type Route struct {
+	Pattern string // path pattern as given to New
+	Name    string // route name, empty unless Named
+
+	meta []Meta // attached by With
+}
+
+// Matched returns the route, which has matched
+// the request, until it is served or recycled.
+// Returns nil if the request was not routed by New.
+func Matched(req *http.Request) *Route {
+	if p, _ := req.Body.(*parameters); p != nil {
+		return p.route
+	}
+	return nil
+}
+
+// Match is filled with the route and a copy of
+// path parameters, when the tracked request is
+// served by the matched route.
+type Match struct {
+	Route  *Route // nil if the request was not served by any route
+	Params Params
+}
+
+type matchKey struct{}
+
+// Track returns a shallow copy of the request, which
+// when served by a route created by New, records the
+// matched route to returned Match. Since the route
+// parameters are recycled when the handler returns, it
+// allows outer middleware, like access logs, to inspect
+// the route after the request was served:
+//
+//	req, m := fastroute.Track(req)
+//	router.ServeHTTP(w, req)
+//	if m.Route != nil {
+//		log.Println(req.Method, m.Route.Pattern, m.Params)
+//	}
+func Track(req *http.Request) (*http.Request, *Match) {
+	m := &Match{}
+	return req.WithContext(context.WithValue(req.Context(), matchKey{}, m)), m
+}
+
+// records the route bound to the tracked request,
+// returns false if the request is not tracked or routed
+func track(req *http.Request) bool {
+	p, _ := req.Body.(*parameters)
+	if p == nil {
+		return false
+	}
+	m, _ := req.Context().Value(matchKey{}).(*Match)
+	if m == nil {
+		return false
+	}
+	m.Route = p.route
+	m.Params = append(m.Params[:0], p.params...)
+	return true
+}
+
+// tracks the request before it is handed over to another goroutine,
+// returns the request which is not tracked anymore, so the route
+// serving it cannot race with the caller reading the Match
+func trackHandover(req *http.Request) *http.Request {
+	if !track(req) {
+		return req
+	}
+	return req.WithContext(context.WithValue(req.Context(), matchKey{}, (*Match)(nil)))
 }
 
 // Recycle resets named parameters
@@ -138,8 +216,9 @@ func Pattern(req *http.Request) string {
 // be invoked to prevent leaking parameters.
 //
 // If the route is not matched and handler is nil,
-// then parameters will not be allocated, same
-// as for static paths.
+// then parameters will not be allocated. Static
+// routes bind empty parameters along with the
+// matched route, so they should be recycled too.
 func Recycle(req *http.Request) {
 	if p, _ := req.Body.(*parameters); p != nil {
 		p.reset(req)
@@ -228,14 +307,66 @@ func (f RouterFunc) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 // add hit counting sorting goroutine, which calculates order
 // based on hits.
 func Chain(routes ...Router) Router {
-	return RouterFunc(func(req *http.Request) http.Handler {
-		for _, router := range routes {
-			if handler := router.Route(req); handler != nil {
-				return handler
-			}
+	return chain(routes)
+}
+
+type chain []Router
+
+func (c chain) Route(req *http.Request) http.Handler {
+	for _, router := range c {
+		if handler := router.Route(req); handler != nil {
+			return handler
 		}
-		return nil
-	})
+	}
+	return nil
+}
+
+func (c chain) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h := c.Route(req); h != nil {
+		h.ServeHTTP(w, req)
+	} else {
+		http.NotFound(w, req)
+	}
+}
+
+// Methods routes the request to the Router registered
+// for the request method, the same way as a map of
+// routers composed with RouterFunc. It gives composed
+// routers the knowledge about the methods available.
+type Methods map[string]Router
+
+// Route delegates the request to the Router
+// registered for the request method, if any.
+func (m Methods) Route(req *http.Request) http.Handler {
+	if r := m[req.Method]; r != nil {
+		return r.Route(req)
+	}
+	return nil
+}
+
+// Allowed returns sorted methods, which have a route
+// matching the request path. Routes are only probed,
+// parameters are recycled.
+func (m Methods) Allowed(req *http.Request) []string {
+	var allows []string
+	for method, router := range m {
+		if h := router.Route(req); h != nil {
+			allows = append(allows, method)
+			Recycle(req) // we will not serve it, need to recycle
+		}
+	}
+	sort.Strings(allows)
+	return allows
+}
+
+// ServeHTTP routes and serves the request,
+// or fallbacks to http.NotFound.
+func (m Methods) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h := m.Route(req); h != nil {
+		h.ServeHTTP(w, req)
+	} else {
+		http.NotFound(w, req)
+	}
 }
 
 // New creates Router which attempts
@@ -254,8 +385,47 @@ func Chain(routes ...Router) Router {
 // When the request is routed, it must be served
 // or recycled in order to salvage allocated named
 // parameters back to the sync.Pool, which dynamically
-// expands or shrinks based on concurrency.
+// expands or shrinks based on concurrency. Pools are
+// shared by all routes and classed by the number of
+// path parameters.
+//
+// New panics with the error message if the path pattern
+// or handler is not valid, use Compile in order to get
+// the error instead.
 func New(path string, handler interface{}) Router {
+	r, err := Compile(path, handler)
+	if err != nil {
+		panic(err.Error())
+	}
+	return r
+}
+
+// Compile is the same as New, but instead of panic
+// returns an error if the path pattern or handler is
+// not valid. Pattern validation failures are reported
+// as *PatternError.
+//
+// It is useful when routes are built from configuration
+// files or other sources which are not known at compile time.
+func Compile(path string, handler interface{}) (Router, error) {
+	r, err := compile("", path, handler)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Named is the same as New, but the route is given
+// a name, which is available from the Route matched.
+func Named(name, path string, handler interface{}) Router {
+	r, err := compile(name, path, handler)
+	if err != nil {
+		panic(err.Error())
+	}
+	return r
+}
+
+func compile(name, path string, handler interface{}) (*route, error) {
 	p := "/" + strings.TrimLeft(path, "/")
 
 	var h http.Handler = nil
@@ -264,75 +434,117 @@ func New(path string, handler interface{}) Router {
 		h = t
 	case func(http.ResponseWriter, *http.Request):
 		h = http.HandlerFunc(t)
+	case http.Handler:
+		h = t
 	case nil:
-		panic("given handler cannot be: nil")
+		return nil, errors.New("given handler cannot be: nil")
 	default:
-		panic(fmt.Sprintf("not a handler given: %T - %+v", t, t))
+		return nil, fmt.Errorf("not a handler given: %T - %+v", t, t)
 	}
 
 	// maybe static route
 	if strings.IndexAny(p, ":*") == -1 {
-		return RouterFunc(func(req *http.Request) http.Handler {
-			if p == req.URL.Path {
-				return h
-			}
-			return nil
-		})
+		return newRoute(&Route{Pattern: p, Name: name}, h, nil), nil
 	}
 
 	// prepare and validate pattern segments to match
 	segments := strings.Split(strings.Trim(p, "/"), "/")
 	for i, seg := range segments {
 		segments[i] = "/" + seg
-		if pos := strings.IndexAny(seg, ":*"); pos == -1 {
-			continue
-		} else if pos != 0 {
-			panic("special param matching signs, must follow after slash: " + p)
-		} else if len(seg)-1 == pos {
-			panic("param must be named after sign: " + p)
-		} else if seg[0] == '*' && i+1 != len(segments) {
-			panic("match all, must be the last segment in pattern: " + p)
-		} else if strings.IndexAny(seg[1:], ":*") != -1 {
-			panic("only one param per segment: " + p)
+		if err := validate(seg, i == len(segments)-1); err != nil {
+			return nil, &PatternError{Pattern: p, Segment: i, Err: err}
 		}
 	}
-	ts := p[len(p)-1] == '/' // whether we need to match trailing slash
+	return newRoute(&Route{Pattern: p, Name: name}, h, segments), nil
+}
+
+func newRoute(info *Route, h http.Handler, segments []string) *route {
+	p := info.Pattern
+	r := &route{info: info, handler: h, segments: segments}
+	r.ts = p[len(p)-1] == '/' // whether we need to match trailing slash
+	r.handle = http.HandlerFunc(r.serve)
+	r.class = sizeClass(strings.Count(p, ":") + strings.Count(p, "*"))
+	return r
+}
 
-	// pool for parameters
-	num := strings.Count(p, ":") + strings.Count(p, "*")
-	pool := sync.Pool{}
-	pool.New = func() interface{} {
-		return &parameters{params: make(Params, 0, num), pool: &pool, pattern: p}
+// route is the Router created by New
+type route struct {
+	info     *Route
+	handler  http.Handler
+	handle   http.Handler // handler extended to salvage parameters
+	segments []string     // nil for static route
+	ts       bool
+	class    int // parameters pool size class
+}
+
+// Route matches the request path and binds
+// parameters along with the route to the request
+func (r *route) Route(req *http.Request) http.Handler {
+	if r.segments == nil {
+		if r.info.Pattern != req.URL.Path {
+			return nil
+		}
+		return r.bind(req, acquire(r.class))
 	}
 
-	// extend handler in order to salvage parameters
-	handle := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+	ps := acquire(r.class)
+	if match(r.segments, req.URL.Path, &ps.params, r.ts) {
+		return r.bind(req, ps)
+	}
+	ps.params = ps.params[0:0]
+	ps.pool.Put(ps)
+	return nil
+}
+
+func (r *route) bind(req *http.Request, ps *parameters) http.Handler {
+	ps.route = r.info
+	ps.body, ps.ReadCloser = req.Body, req.Body
+	req.Body = ps
+	return r.handle
+}
+
+func (r *route) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h := r.Route(req); h != nil {
 		h.ServeHTTP(w, req)
-		if p, _ := req.Body.(*parameters); p != nil {
-			p.reset(req)
-		}
-	})
-
-	// dynamic route matcher
-	return RouterFunc(func(req *http.Request) http.Handler {
-		ps := pool.Get().(*parameters)
-		if match(segments, req.URL.Path, &ps.params, ts) {
-			ps.ReadCloser = req.Body
-			req.Body = ps
-			return handle
-		}
-		ps.params = ps.params[0:0]
-		pool.Put(ps)
+	} else {
+		http.NotFound(w, req)
+	}
+}
+
+func (r *route) serve(w http.ResponseWriter, req *http.Request) {
+	track(req)
+	r.handler.ServeHTTP(w, req)
+	if p, _ := req.Body.(*parameters); p != nil {
+		p.reset(req)
+	}
+}
+
+// validates pattern segment, without the leading slash
+func validate(seg string, last bool) error {
+	switch pos := strings.IndexAny(seg, ":*"); {
+	case pos == -1:
 		return nil
-	})
+	case pos != 0:
+		return ErrParamPosition
+	case len(seg)-1 == pos:
+		return ErrParamUnnamed
+	case seg[0] == '*' && !last:
+		return ErrCatchAllPosition
+	case strings.IndexAny(seg[1:], ":*") != -1:
+		return ErrParamsPerSegment
+	}
+	return nil
 }
 
-// matches pattern segments to an url and pushes named parameters to ps
+// matches pattern segments to an url and pushes named parameters to ps,
+// empty segment "/" of the pattern is matched as static
 func match(segments []string, url string, ps *Params, ts bool) bool {
 	for _, segment := range segments {
 		switch {
 		case len(url) == 0 || url[0] != '/':
 			return false
+		case len(segment) == 1:
+			url = url[1:]
 		case segment[1] == ':' && len(url) > 1:
 			end := 1
 			for end < len(url) && url[end] != '/' {
@@ -352,15 +564,92 @@ func match(segments []string, url string, ps *Params, ts bool) bool {
 	return (!ts && url == "") || (ts && url == "/") // match trailing slash
 }
 
+// serves h with r, which is derived from req, for example
+// by WithContext. When the route recycles parameters of r,
+// the request body of req must be restored the same way.
+func serveDerived(h http.Handler, w http.ResponseWriter, req, r *http.Request) {
+	h.ServeHTTP(w, r)
+	if r != req {
+		req.Body = r.Body
+	}
+}
+
+// pools of parameters shared by all routes, where size class
+// c holds parameters with capacity for 1<<(c-1) params, except
+// class 0 used by static routes
+var pools [64]sync.Pool
+
+// resolves pool size class for given number of params
+func sizeClass(num int) int {
+	if num == 0 {
+		return 0
+	}
+	return bits.Len(uint(num-1)) + 1
+}
+
+func acquire(class int) *parameters {
+	if p, _ := pools[class].Get().(*parameters); p != nil {
+		return p
+	}
+	var size int
+	if class > 0 {
+		size = 1 << uint(class-1)
+	}
+	return &parameters{params: make(Params, 0, size), pool: &pools[class]}
+}
+
+// parameters are bound to the request in place of the request
+// body, which may be limited, the original body is restored on reset
 type parameters struct {
 	io.ReadCloser
-	params  Params
-	pattern string
-	pool    *sync.Pool
+	body   io.ReadCloser
+	params Params
+	route  *Route
+	values []value // request scoped values, like API version
+	pool   *sync.Pool
+}
+
+type value struct {
+	key, val interface{}
 }
 
 func (p *parameters) reset(req *http.Request) {
-	req.Body = p.ReadCloser
+	req.Body = p.body
+	p.ReadCloser, p.body = nil, nil
 	p.params = p.params[0:0]
+	p.route = nil
+	for i := range p.values {
+		p.values[i] = value{} // do not retain values in the pool
+	}
+	p.values = p.values[0:0]
 	p.pool.Put(p)
 }
+
+// binds request scoped value along with route parameters,
+// returns false if the request was not routed by New
+func set(req *http.Request, key, val interface{}) bool {
+	p, _ := req.Body.(*parameters)
+	if p == nil {
+		return false
+	}
+	for i := range p.values {
+		if p.values[i].key == key {
+			p.values[i].val = val
+			return true
+		}
+	}
+	p.values = append(p.values, value{key, val})
+	return true
+}
+
+// returns request scoped value bound along with route parameters
+func get(req *http.Request, key interface{}) interface{} {
+	if p, _ := req.Body.(*parameters); p != nil {
+		for i := range p.values {
+			if p.values[i].key == key {
+				return p.values[i].val
+			}
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package fastroute_test
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"net/http"
@@ -111,7 +112,7 @@ func TestRaceConditionForMatchingSingleStaticRoute(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 		if w.Code != 200 {
-			t.Fatal("expected OK status")
+			t.Error("expected OK status")
 		}
 		wg.Done()
 	}
@@ -137,7 +138,7 @@ func TestRaceConditionForMatchingSingleDynamicRoute(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 		if w.Code != 200 {
-			t.Fatal("expected OK status")
+			t.Error("expected OK status")
 		}
 		wg.Done()
 	}
@@ -355,6 +356,89 @@ func TestRoutePatternValidation(t *testing.T) {
 	recoverOrFail("/path", "given handler cannot be: nil", nil, t)
 }
 
+func TestCompileReturnsPatternError(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		pattern string
+		segment int
+		reason  error
+	}{
+		{"/path/*", 1, fastroute.ErrParamUnnamed},
+		{"/pa:/a", 0, fastroute.ErrParamPosition},
+		{"/a/:user:/id", 1, fastroute.ErrParamsPerSegment},
+		{"/path/*all/more", 1, fastroute.ErrCatchAllPosition},
+	}
+
+	for _, c := range cases {
+		r, err := fastroute.Compile(c.pattern, http.NotFoundHandler())
+		if r != nil {
+			t.Fatalf("expected no router for invalid pattern: %s", c.pattern)
+		}
+
+		var perr *fastroute.PatternError
+		if !errors.As(err, &perr) {
+			t.Fatalf("expected pattern error for: %s, but got: %v", c.pattern, err)
+		}
+		if perr.Pattern != c.pattern || perr.Segment != c.segment || !errors.Is(err, c.reason) {
+			t.Fatalf("unexpected pattern error: %+v for: %s", perr, c.pattern)
+		}
+	}
+
+	if _, err := fastroute.Compile("/path", nil); err == nil {
+		t.Fatal("expected an error for nil handler")
+	}
+
+	if _, err := fastroute.Compile("/users/:id", http.NotFoundHandler()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestTrackMatchedRouteAfterServing(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		if route := fastroute.Matched(req); route == nil || route.Pattern != fastroute.Pattern(req) {
+			t.Errorf("expected matched route while serving, but got: %+v", route)
+		}
+	}
+	router := fastroute.Chain(
+		fastroute.Named("status", "/status", handler),
+		fastroute.Named("user", "/users/:id", handler),
+		fastroute.New("/files/*path", handler),
+	)
+
+	cases := []struct {
+		path, pattern, name string
+		params              fastroute.Params
+	}{
+		{"/status", "/status", "status", fastroute.Params{}},
+		{"/users/5", "/users/:id", "user", fastroute.Params{{"id", "5"}}},
+		{"/files/a/b", "/files/*path", "", fastroute.Params{{"path", "/a/b"}}},
+	}
+
+	for _, c := range cases {
+		req, _ := http.NewRequest("GET", c.path, nil)
+		req, m := fastroute.Track(req)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		if fastroute.Matched(req) != nil {
+			t.Fatalf("expected route to be recycled after serving: %s", c.path)
+		}
+		if m.Route == nil || m.Route.Pattern != c.pattern || m.Route.Name != c.name {
+			t.Fatalf("unexpected tracked route: %+v for: %s", m.Route, c.path)
+		}
+		if fmt.Sprint(m.Params) != fmt.Sprint(c.params) {
+			t.Fatalf("unexpected tracked params: %v for: %s", m.Params, c.path)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/unknown", nil)
+	req, m := fastroute.Track(req)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	if m.Route != nil {
+		t.Fatalf("did not expect tracked route, but got: %+v", m.Route)
+	}
+}
+
 func TestStaticRouteMatcher(t *testing.T) {
 	t.Parallel()
 	cases := map[string]bool{
@@ -488,6 +572,34 @@ func TestDynamicRouteMatcher(t *testing.T) {
 	}
 }
 
+func TestParametersOfAllSizeClasses(t *testing.T) {
+	t.Parallel()
+	for num := 1; num <= 17; num++ {
+		var pattern, path string
+		for i := 0; i < num; i++ {
+			pattern += fmt.Sprintf("/p/:p%d", i)
+			path += fmt.Sprintf("/p/%d", i)
+		}
+
+		router := fastroute.New(pattern, func(w http.ResponseWriter, req *http.Request) {
+			params := fastroute.Parameters(req)
+			if len(params) != num || params.ByName(fmt.Sprintf("p%d", num-1)) != fmt.Sprint(num-1) {
+				t.Errorf("unexpected params: %v for pattern: %s", params, pattern)
+			}
+		})
+
+		// served twice to reuse pooled parameters
+		for i := 0; i < 2; i++ {
+			req, _ := http.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != 200 {
+				t.Fatalf("expected to match: %s", path)
+			}
+		}
+	}
+}
+
 func TestGenerated(t *testing.T) {
 	routes, pat := generateRoutes(60, 5)
 	pat = strings.Replace(pat, ":id", "param", 1)
@@ -519,6 +631,46 @@ func Benchmark_1Param(b *testing.B) {
 	benchmark(b, router, req)
 }
 
+func Benchmark_1Param_Parallel(b *testing.B) {
+	router := fastroute.New("/v1/users/:id", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fastroute.Parameters(r).ByName("id")))
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		req, _ := http.NewRequest("GET", "/v1/users/5", nil)
+		for pb.Next() {
+			router.Route(req)
+			fastroute.Recycle(req)
+		}
+	})
+}
+
+func Benchmark_1000Routes_AllMatched_Parallel(b *testing.B) {
+	routes, _ := generateRoutes(1000, 3)
+	var paths []string
+	for _, r := range routes {
+		fastroute.Walk(r, func(method string, route *fastroute.Route) error {
+			paths = append(paths, strings.Replace(route.Pattern, ":id", "param", 1))
+			return nil
+		})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		var i int
+		for pb.Next() {
+			i = (i + 1) % len(routes)
+			req.URL.Path = paths[i]
+			routes[i].Route(req)
+			fastroute.Recycle(req)
+		}
+	})
+}
+
 func Benchmark_Static(b *testing.B) {
 	router := fastroute.New("/static/path/pattern", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("OK"))
@@ -616,7 +768,10 @@ func (s SortByHits) Less(i, j int) bool { return s[i].hits > s[j].hits }
 func recoverOrFail(pattern, expectedMessage string, h interface{}, t *testing.T) {
 	defer func() {
 		if err := recover(); err != nil {
-			actual := fmt.Sprintf("%s", err)
+			actual, ok := err.(string)
+			if !ok {
+				t.Fatalf(`expected panic value to be a string, but got: %T`, err)
+			}
 			if actual != expectedMessage {
 				t.Fatalf(`actual message: "%s" does not match expected: "%s"`, actual, expectedMessage)
 			}
@@ -0,0 +1,97 @@
+package fastroute
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// SPA creates Router for single page applications, which
+// serves files from fs the same way as Files, and falls back
+// to "/index.html" for unknown paths, so the application can
+// route them on the client side.
+//
+// The fallback applies only to GET or HEAD requests accepting
+// HTML, for paths without a file extension. So the API clients
+// and missing assets get 404 Not Found instead of HTML. Other
+// requests are not routed, that way API routes chained before
+// can be guarded to respond with proper errors:
+//
+//	fastroute.Chain(
+//		api,
+//		fastroute.New("/api/*rest", http.NotFoundHandler()),
+//		fastroute.SPA("/*filepath", http.FS(assets)),
+//	)
+func SPA(pattern string, fs http.FileSystem) Router {
+	server := &fileServer{fs: fs}
+	return &spa{files: files(pattern, server), server: server}
+}
+
+type spa struct {
+	files  Router
+	server *fileServer
+}
+
+func (s *spa) Route(req *http.Request) http.Handler {
+	h := s.files.Route(req)
+	if h == nil {
+		return nil
+	}
+
+	params := Parameters(req)
+	name, ok := cleanName(params[len(params)-1].Value)
+	if ok && s.exists(name) {
+		return h
+	}
+	Recycle(req) // fallback is served without the route
+
+	if !s.fallback(req) {
+		return nil
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.server.serve(w, req, "/index.html")
+	})
+}
+
+func (s *spa) exists(name string) bool {
+	f, info, err := s.server.open(name)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	if !info.IsDir() {
+		return true
+	}
+	if f, info, err = s.server.open(path.Join(name, "index.html")); err != nil {
+		return false
+	}
+	f.Close()
+	return !info.IsDir()
+}
+
+// whether the request is likely a client side application route
+func (s *spa) fallback(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	if path.Ext(req.URL.Path) != "" {
+		return false
+	}
+	accept := req.Header.Get("Accept")
+	return strings.Contains(accept, "text/html") && quality(accept, "text/html") > 0
+}
+
+func (s *spa) unwrap() Router { return s.files }
+func (s *spa) String() string { return "SPA" }
+
+func (s *spa) rewrap(router Router) Router {
+	return &spa{files: router, server: s.server}
+}
+
+func (s *spa) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h := s.Route(req); h != nil {
+		h.ServeHTTP(w, req)
+	} else {
+		http.NotFound(w, req)
+	}
+}
@@ -0,0 +1,57 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestSPAFallback(t *testing.T) {
+	t.Parallel()
+	fs := fstest.MapFS{
+		"index.html":      {Data: []byte("<app>")},
+		"js/app.js":       {Data: []byte("app()")},
+		"docs/index.html": {Data: []byte("<docs>")},
+	}
+	api := fastroute.New("/api/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("user " + fastroute.Parameters(req).ByName("id")))
+	})
+	router := fastroute.Chain(
+		api,
+		fastroute.New("/api/*rest", http.NotFoundHandler()),
+		fastroute.SPA("/*filepath", http.FS(fs)),
+	)
+
+	html := "text/html,application/xhtml+xml,*/*;q=0.8"
+	cases := []struct {
+		method, path, accept string
+		code                 int
+		body                 string
+	}{
+		{"GET", "/api/users/1", "application/json", 200, "user 1"},
+		{"GET", "/api/unknown", html, 404, "404 page not found\n"},
+		{"GET", "/js/app.js", "*/*", 200, "app()"},
+		{"GET", "/js/missing.js", html, 404, "404 page not found\n"},
+		{"GET", "/docs/", html, 200, "<docs>"},
+		{"GET", "/users/1/settings", html, 200, "<app>"},
+		{"GET", "/users/1/settings", "application/json", 404, "404 page not found\n"},
+		{"POST", "/users/1/settings", html, 404, "404 page not found\n"},
+		{"GET", "/", html, 200, "<app>"},
+	}
+
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, c.path, nil)
+		req.Header.Set("Accept", c.accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != c.code || w.Body.String() != c.body {
+			t.Fatalf("unexpected response for %s %s: %d %q", c.method, c.path, w.Code, w.Body.String())
+		}
+		if fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters to be recycled for: %s", c.path)
+		}
+	}
+}
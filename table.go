@@ -0,0 +1,207 @@
+package fastroute
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Table is a declarative route table document. It can
+// be decoded from JSON with Load, or from YAML or any other
+// format by the decoder of choice and then compiled.
+//
+// An example JSON document:
+//
+//	{"routes": [
+//	  {"name": "users", "pattern": "/users", "methods": ["GET"], "handler": "users.list"},
+//	  {"name": "user", "pattern": "/users/:id", "methods": ["GET", "PUT"], "handler": "users.show"}
+//	]}
+type Table struct {
+	Routes []TableRoute `json:"routes" yaml:"routes"`
+}
+
+// TableRoute is a single route table entry, binding path
+// pattern and request methods to the handler registered
+// by the given key.
+type TableRoute struct {
+	Name    string   `json:"name,omitempty" yaml:"name,omitempty"`
+	Pattern string   `json:"pattern" yaml:"pattern"`
+	Methods []string `json:"methods" yaml:"methods"`
+	Handler string   `json:"handler" yaml:"handler"`
+
+	line, column int // position in the loaded document
+}
+
+// TableError describes an invalid route table document
+// or entry. Route is the index of the offending entry or -1
+// if the document itself is malformed. Line and Column
+// point to the position in the document when it is known.
+type TableError struct {
+	Line, Column int
+	Route        int
+	Field        string
+	Err          error
+}
+
+func (e *TableError) Error() string {
+	var pos string
+	if e.Line > 0 {
+		pos = fmt.Sprintf("line %d, column %d: ", e.Line, e.Column)
+	}
+	if e.Route < 0 {
+		return pos + e.Err.Error()
+	}
+	return pos + fmt.Sprintf("routes[%d].%s: %s", e.Route, e.Field, e.Err)
+}
+
+// Unwrap returns the underlying error, for example
+// *PatternError if the route pattern is not valid.
+func (e *TableError) Unwrap() error {
+	return e.Err
+}
+
+// Load decodes JSON route table document from r and compiles
+// it to the method aware Router, resolving handler keys from
+// the given handlers registry.
+func Load(r io.Reader, handlers map[string]http.Handler) (Router, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var t Table
+	if err := t.decode(data); err != nil {
+		return nil, err
+	}
+	return t.Compile(handlers)
+}
+
+// Compile validates all route table entries with the same
+// rules as New and builds Methods router, where routes for
+// every method are chained in the order of the table.
+func (t *Table) Compile(handlers map[string]http.Handler) (Router, error) {
+	chains := make(map[string][]Router)
+	names := make(map[string]int)
+	for i, tr := range t.Routes {
+		fail := func(field string, err error) error {
+			return &TableError{Line: tr.line, Column: tr.column, Route: i, Field: field, Err: err}
+		}
+
+		if tr.Name != "" {
+			if j, dup := names[tr.Name]; dup {
+				return nil, fail("name", fmt.Errorf(`"%s" is already used by routes[%d]`, tr.Name, j))
+			}
+			names[tr.Name] = i
+		}
+
+		if len(tr.Methods) == 0 {
+			return nil, fail("methods", errors.New("at least one method must be given"))
+		}
+
+		h, ok := handlers[tr.Handler]
+		if !ok || h == nil {
+			return nil, fail("handler", fmt.Errorf(`unknown handler key "%s"`, tr.Handler))
+		}
+
+		route, err := compile(tr.Name, tr.Pattern, h)
+		if err != nil {
+			return nil, fail("pattern", err)
+		}
+
+		for _, method := range tr.Methods {
+			method = strings.ToUpper(method)
+			chains[method] = append(chains[method], route)
+		}
+	}
+
+	routes := make(Methods, len(chains))
+	for method, chain := range chains {
+		routes[method] = Chain(chain...)
+	}
+	return routes, nil
+}
+
+// decodes JSON document, remembering positions of route entries
+func (t *Table) decode(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	fail := func(err error) error {
+		offset := dec.InputOffset()
+		switch e := err.(type) {
+		case *json.SyntaxError:
+			offset = e.Offset - 1 // offending character is already read
+		case *json.UnmarshalTypeError:
+			offset = e.Offset
+		case nil:
+			err = errors.New("unexpected end of document")
+		}
+		line, col := position(data, offset)
+		return &TableError{Line: line, Column: col, Route: -1, Err: err}
+	}
+	expect := func(delim json.Delim) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return fail(err)
+		}
+		if tok != delim {
+			return fail(fmt.Errorf("expected '%s', but got: %v", delim, tok))
+		}
+		return nil
+	}
+
+	if err := expect('{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return fail(err)
+		}
+		if key != "routes" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return fail(err)
+			}
+			continue
+		}
+
+		if err := expect('['); err != nil {
+			return err
+		}
+		for dec.More() {
+			var tr TableRoute
+			// offset points to the separator before the entry
+			tr.line, tr.column = position(data, skipSpace(data, dec.InputOffset()))
+			if err := dec.Decode(&tr); err != nil {
+				return fail(err)
+			}
+			t.Routes = append(t.Routes, tr)
+		}
+		if err := expect(']'); err != nil {
+			return err
+		}
+	}
+	return expect('}')
+}
+
+// skips separators and white space from offset
+func skipSpace(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) && strings.IndexByte(", \t\r\n", data[offset]) != -1 {
+		offset++
+	}
+	return offset
+}
+
+// resolves line and column, both starting from 1, for given byte offset
+func position(data []byte, offset int64) (line, col int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line = bytes.Count(before, []byte{'\n'}) + 1
+	col = int(offset) - bytes.LastIndexByte(before, '\n')
+	return
+}
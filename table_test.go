@@ -0,0 +1,87 @@
+package fastroute_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestLoadRouteTable(t *testing.T) {
+	t.Parallel()
+	handler := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprintf(w, "%s:%s", name, fastroute.Parameters(req).ByName("id"))
+		})
+	}
+	handlers := map[string]http.Handler{
+		"users.list": handler("list"),
+		"users.show": handler("show"),
+	}
+
+	router, err := fastroute.Load(strings.NewReader(`{"routes": [
+		{"name": "users", "pattern": "/users", "methods": ["GET"], "handler": "users.list"},
+		{"name": "user", "pattern": "/users/:id", "methods": ["get", "PUT"], "handler": "users.show"}
+	]}`), handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		method, path string
+		code         int
+		body         string
+	}{
+		{"GET", "/users", 200, "list:"},
+		{"GET", "/users/5", 200, "show:5"},
+		{"PUT", "/users/5", 200, "show:5"},
+		{"POST", "/users/5", 404, "404 page not found\n"},
+	}
+
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, c.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != c.code || w.Body.String() != c.body {
+			t.Fatalf("unexpected response for %s %s: %d %q", c.method, c.path, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestLoadRouteTableErrors(t *testing.T) {
+	t.Parallel()
+	handlers := map[string]http.Handler{"h": http.NotFoundHandler()}
+
+	cases := map[string]string{
+		`{"routes": [
+  {"pattern": "/a", "methods": ["GET"], "handler": "h"},
+  {"pattern": "/b/:", "methods": ["GET"], "handler": "h"}
+]}`: "line 3, column 3: routes[1].pattern: param must be named after sign: /b/:",
+		`{"routes": [{"pattern": "/a", "methods": ["GET"], "handler": "x"}]}`: `line 1, column 13: routes[0].handler: unknown handler key "x"`,
+		`{"routes": [{"pattern": "/a", "handler": "h"}]}`:                     "line 1, column 13: routes[0].methods: at least one method must be given",
+		`{"routes": [
+  {"name": "a", "pattern": "/a", "methods": ["GET"], "handler": "h"},
+  {"name": "a", "pattern": "/b", "methods": ["GET"], "handler": "h"}
+]}`: `line 3, column 3: routes[1].name: "a" is already used by routes[0]`,
+		"{\"routes\": [\n  {\"pattern\": \"/a\",}\n]}": "line 2, column 20: invalid character '}' looking for beginning of object key string",
+		`{"routes": {}}`: "line 1, column 13: expected '[', but got: {",
+	}
+
+	for doc, expected := range cases {
+		_, err := fastroute.Load(strings.NewReader(doc), handlers)
+		if err == nil {
+			t.Fatalf("expected an error for document: %s", doc)
+		}
+		var terr *fastroute.TableError
+		if !errors.As(err, &terr) {
+			t.Fatalf("expected table error, but got: %T", err)
+		}
+		if err.Error() != expected {
+			t.Fatalf(`actual error: "%s" does not match expected: "%s"`, err, expected)
+		}
+	}
+}
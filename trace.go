@@ -0,0 +1,124 @@
+package fastroute
+
+import (
+	"context"
+	"net/http"
+)
+
+// Hook is notified by Hooked router about every
+// routed request when it is served.
+type Hook interface {
+	// Match is invoked before the matched handler is
+	// served, while the route pattern and parameters are
+	// still available. Pattern is empty if the request was
+	// not matched and is served as not found, or was routed
+	// by a router without path patterns. The returned
+	// request, which may be req with a derived context,
+	// is served instead.
+	Match(req *http.Request, pattern string, params Params) *http.Request
+
+	// Complete is invoked after the handler returns,
+	// with the request returned by Match and the
+	// response status.
+	Complete(req *http.Request, status int)
+}
+
+// Hooked wraps router in order to notify hook about every
+// request it routes, when the request is served.
+// Requests which cannot be routed are passed on as nil
+// handler, so the following routers in the Chain may still
+// match.
+func Hooked(router Router, hook Hook) Router {
+	return &hooked{router: router, hook: hook}
+}
+
+type hooked struct {
+	router Router
+	hook   Hook
+}
+
+func (h *hooked) Route(req *http.Request) http.Handler {
+	if handler := h.router.Route(req); handler != nil {
+		return h.notify(handler, true)
+	}
+	return nil
+}
+
+func (h *hooked) unwrap() Router              { return h.router }
+func (h *hooked) rewrap(router Router) Router { return Hooked(router, h.hook) }
+func (h *hooked) String() string              { return "Hooked" }
+
+func (h *hooked) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if handler := h.Route(req); handler != nil {
+		handler.ServeHTTP(w, req)
+	} else {
+		h.notify(http.NotFoundHandler(), false).ServeHTTP(w, req)
+	}
+}
+
+func (h *hooked) notify(handler http.Handler, matched bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var pattern string
+		if route := Matched(req); matched && route != nil {
+			pattern = route.Pattern
+		}
+		r := h.hook.Match(req, pattern, Parameters(req))
+		sw := &statusWriter{ResponseWriter: w}
+		serveDerived(handler, sw, req, r)
+		h.hook.Complete(r, sw.Status())
+	})
+}
+
+// Tracer is the subset of a tracing API, like
+// OpenTelemetry trace.Tracer, used by Trace hook.
+// It can be implemented by a thin adapter.
+type Tracer interface {
+	// Start creates a span and a context containing it.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation started by Tracer.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	End()
+}
+
+type spanKey struct{}
+
+// Trace creates a Hook, which starts a server span for
+// every served request named by the request method and
+// the matched route pattern, for example "GET /users/:id",
+// or only by the method if there is no route pattern.
+// Route and path parameters are set as span attributes,
+// following OpenTelemetry HTTP semantic conventions.
+func Trace(tracer Tracer) Hook {
+	return tracing{tracer}
+}
+
+type tracing struct {
+	tracer Tracer
+}
+
+func (t tracing) Match(req *http.Request, pattern string, params Params) *http.Request {
+	name := req.Method
+	if pattern != "" {
+		name += " " + pattern
+	}
+	ctx, span := t.tracer.Start(req.Context(), name)
+	span.SetAttribute("http.request.method", req.Method)
+	span.SetAttribute("url.path", req.URL.Path)
+	if pattern != "" {
+		span.SetAttribute("http.route", pattern)
+	}
+	for _, p := range params {
+		span.SetAttribute("http.route.param."+p.Key, p.Value)
+	}
+	return req.WithContext(context.WithValue(ctx, spanKey{}, span))
+}
+
+func (t tracing) Complete(req *http.Request, status int) {
+	if span, ok := req.Context().Value(spanKey{}).(Span); ok {
+		span.SetAttribute("http.response.status_code", status)
+		span.End()
+	}
+}
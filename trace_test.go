@@ -0,0 +1,85 @@
+package fastroute_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+// in memory span exporter
+type memTracer struct {
+	mu    sync.Mutex
+	spans []*memSpan
+}
+
+type memSpan struct {
+	name  string
+	attrs map[string]interface{}
+	ended bool
+}
+
+func (t *memTracer) Start(ctx context.Context, name string) (context.Context, fastroute.Span) {
+	span := &memSpan{name: name, attrs: make(map[string]interface{})}
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+	return ctx, span
+}
+
+func (s *memSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *memSpan) End()                                       { s.ended = true }
+
+func TestTraceSpansByRoutePattern(t *testing.T) {
+	t.Parallel()
+	tracer := &memTracer{}
+	router := fastroute.Hooked(fastroute.Chain(
+		fastroute.New("/status", func(w http.ResponseWriter, req *http.Request) {}),
+		fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+			if fastroute.Parameters(req).ByName("id") != "5" {
+				t.Error("expected parameters to be available in handler")
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}),
+		fastroute.RouterFunc(func(req *http.Request) http.Handler {
+			if req.URL.Path == "/legacy/123" {
+				return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {})
+			}
+			return nil
+		}),
+	), fastroute.Trace(tracer))
+
+	for _, path := range []string{"/users/5", "/status", "/unknown", "/legacy/123"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		if fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters to be recycled for: %s", path)
+		}
+	}
+
+	expected := []struct {
+		name   string
+		route  interface{}
+		status int
+	}{
+		{"GET /users/:id", "/users/:id", 202},
+		{"GET /status", "/status", 200},
+		{"GET", nil, 404},
+		{"GET", nil, 200}, // no route pattern
+	}
+	if len(tracer.spans) != len(expected) {
+		t.Fatalf("expected %d spans, but got: %d", len(expected), len(tracer.spans))
+	}
+	for i, e := range expected {
+		span := tracer.spans[i]
+		if span.name != e.name || span.attrs["http.route"] != e.route || span.attrs["http.response.status_code"] != e.status || !span.ended {
+			t.Fatalf("unexpected span: %+v, expected: %+v", span, e)
+		}
+	}
+	if tracer.spans[0].attrs["http.route.param.id"] != "5" {
+		t.Fatalf("expected param attribute, but got: %+v", tracer.spans[0].attrs)
+	}
+}
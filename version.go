@@ -0,0 +1,167 @@
+package fastroute
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Version binds version specific Router to the version name,
+// like "v2". If the version is selected by path prefix, like
+// "/v2/users", version routers match the path without it, so
+// the same patterns serve the version requested by any means
+// and older versions can serve newer ones.
+type Version struct {
+	Name   string
+	Router Router
+}
+
+// Versioning configures how Versioned selects the API version.
+// The version is taken from the first path segment, the Header
+// or the Accept header vendor MediaType, in this order. If no
+// version is requested, the latest one is used.
+type Versioning struct {
+	// Versions ordered from the oldest to the latest.
+	Versions []Version
+
+	// Header optionally names request header
+	// carrying the version, like "API-Version".
+	Header string
+
+	// MediaType is an optional vendor media type prefix, like
+	// "application/vnd.x.", which selects version "v2" given
+	// Accept: application/vnd.x.v2+json
+	MediaType string
+}
+
+type versionKey struct{}
+
+// APIVersion returns the name of the version which
+// has routed the request by Versioned router, until
+// the request is served or recycled.
+func APIVersion(req *http.Request) string {
+	v, _ := get(req, versionKey{}).(string)
+	return v
+}
+
+// Versioned creates Router choosing among version specific
+// routers. When the router of the requested version cannot
+// route the request, it falls back to the older versions,
+// so newer versions need to register only changed routes.
+// Requests for unknown versions are not routed.
+//
+// The name of the version which has routed the request is
+// available by APIVersion, along with Params, if the request
+// was routed by New. The version prefix is stripped from the
+// URL path of the request copy, which is routed and served
+// by the version router, the same as done by http.StripPrefix.
+// The request itself is not modified.
+func Versioned(v Versioning) Router {
+	return &versioned{v}
+}
+
+type versioned struct {
+	Versioning
+}
+
+func (v *versioned) Route(req *http.Request) http.Handler {
+	i, prefixed := v.pathVersion(req)
+	if !prefixed {
+		i = v.requested(req)
+	}
+	if i < 0 {
+		return nil
+	}
+
+	r, prefix := req, "/"+v.Versions[i].Name
+	if prefixed {
+		r = withoutPrefix(req, prefix)
+	}
+	for ; i >= 0; i-- {
+		h := v.Versions[i].Router.Route(r)
+		if h == nil {
+			continue
+		}
+		set(r, versionKey{}, v.Versions[i].Name)
+		if !prefixed {
+			return h
+		}
+		req.Body = r.Body // so that the request can be recycled
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			serveDerived(h, w, req, withoutPrefix(req, prefix))
+		})
+	}
+	return nil
+}
+
+// resolves version index by the first path segment
+func (v *versioned) pathVersion(req *http.Request) (int, bool) {
+	if seg := strings.SplitN(strings.TrimPrefix(req.URL.Path, "/"), "/", 2); len(seg) == 2 {
+		for i := range v.Versions {
+			if v.Versions[i].Name == seg[0] {
+				return i, true
+			}
+		}
+	}
+	return -1, false
+}
+
+// resolves requested version index, or -1 if it is not known
+func (v *versioned) requested(req *http.Request) int {
+	name := v.requestedName(req)
+	if name == "" {
+		return len(v.Versions) - 1
+	}
+	for i := range v.Versions {
+		if v.Versions[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (v *versioned) requestedName(req *http.Request) string {
+	if v.Header != "" {
+		if name := req.Header.Get(v.Header); name != "" {
+			return name
+		}
+	}
+
+	if v.MediaType != "" {
+		// the most preferred vendor media type, the first one if equally preferred
+		var best string
+		var bestQ float64
+		for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+			parts := strings.Split(accept, ";")
+			mt := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(mt, v.MediaType) {
+				continue
+			}
+			if q := qvalue(parts[1:]); q > bestQ {
+				best, bestQ = mt[len(v.MediaType):], q
+			}
+		}
+		if end := strings.IndexByte(best, '+'); end != -1 {
+			best = best[:end]
+		}
+		return best
+	}
+	return ""
+}
+
+// shallow copy of the request, with the path prefix stripped from the URL
+func withoutPrefix(req *http.Request, prefix string) *http.Request {
+	r := req.WithContext(req.Context())
+	u := *req.URL
+	u.Path = strings.TrimPrefix(req.URL.Path, prefix)
+	u.RawPath = strings.TrimPrefix(req.URL.RawPath, prefix)
+	r.URL = &u
+	return r
+}
+
+func (v *versioned) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h := v.Route(req); h != nil {
+		h.ServeHTTP(w, req)
+	} else {
+		http.NotFound(w, req)
+	}
+}
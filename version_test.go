@@ -0,0 +1,96 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestVersionedRouting(t *testing.T) {
+	t.Parallel()
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprintf(w, "%s %s %s %s", name, fastroute.APIVersion(req), fastroute.Parameters(req).ByName("id"), req.URL.Path)
+		}
+	}
+
+	router := fastroute.Versioned(fastroute.Versioning{
+		Header:    "API-Version",
+		MediaType: "application/vnd.x.",
+		Versions: []fastroute.Version{
+			{Name: "v1", Router: fastroute.Chain(
+				fastroute.New("/users/:id", handler("users1")),
+				fastroute.New("/orders/:id", handler("orders1")),
+			)},
+			{Name: "v2", Router: fastroute.New("/users/:id", handler("users2"))},
+		},
+	})
+
+	cases := []struct {
+		path, header, accept string
+		code                 int
+		body                 string
+	}{
+		{"/users/1", "", "", 200, "users2 v2 1 /users/1"},
+		{"/users/1", "v1", "", 200, "users1 v1 1 /users/1"},
+		{"/users/1", "", "application/vnd.x.v1+json", 200, "users1 v1 1 /users/1"},
+		{"/users/1", "", "text/html, application/vnd.x.v2+json;q=0.9", 200, "users2 v2 1 /users/1"},
+		{"/users/1", "", "application/vnd.x.v1+json;q=0, application/vnd.x.v2+json;q=0.5", 200, "users2 v2 1 /users/1"},
+		{"/users/1", "", "application/vnd.x.v2+json;q=0.5, application/vnd.x.v1+json", 200, "users1 v1 1 /users/1"},
+		{"/v1/users/1", "v2", "", 200, "users1 v1 1 /users/1"},
+		{"/v2/users/1", "", "", 200, "users2 v2 1 /users/1"},
+		{"/orders/3", "v2", "", 200, "orders1 v1 3 /orders/3"},
+		{"/v2/orders/3", "", "", 200, "orders1 v1 3 /orders/3"},
+		{"/v1/orders/3", "", "", 200, "orders1 v1 3 /orders/3"},
+		{"/v2/posts/3", "", "", 404, "404 page not found\n"},
+		{"/users/1", "v3", "", 404, "404 page not found\n"},
+	}
+
+	for _, c := range cases {
+		req, _ := http.NewRequest("GET", c.path, nil)
+		if c.header != "" {
+			req.Header.Set("API-Version", c.header)
+		}
+		if c.accept != "" {
+			req.Header.Set("Accept", c.accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != c.code || w.Body.String() != c.body {
+			t.Fatalf("unexpected response for %s: %d %q, expected: %q", c.path, w.Code, w.Body.String(), c.body)
+		}
+		if req.URL.Path != c.path || fastroute.Parameters(req) != nil {
+			t.Fatalf("expected request to remain the same after serving, but got: %s", req.URL.Path)
+		}
+	}
+}
+
+func TestVersionedProbeDoesNotModifyRequest(t *testing.T) {
+	t.Parallel()
+	router := fastroute.Versioned(fastroute.Versioning{
+		Versions: []fastroute.Version{
+			{Name: "v1", Router: fastroute.New("/users/:id", http.NotFoundHandler())},
+			{Name: "v2", Router: fastroute.New("/users/:id", http.NotFoundHandler())},
+		},
+	})
+
+	req, _ := http.NewRequest("GET", "/v1/users/1", nil)
+	for i := 0; i < 2; i++ {
+		if h := router.Route(req); h == nil {
+			t.Fatalf("expected request to be routed, attempt: %d", i)
+		}
+		if v := fastroute.APIVersion(req); v != "v1" {
+			t.Fatalf("expected request to be routed by v1, but got: %q, attempt: %d", v, i)
+		}
+		if p := fastroute.Parameters(req).ByName("id"); p != "1" {
+			t.Fatalf("expected id param to be bound, but got: %q, attempt: %d", p, i)
+		}
+		fastroute.Recycle(req)
+		if req.URL.Path != "/v1/users/1" || req.Body != nil {
+			t.Fatalf("expected request to be restored, but got: %s, attempt: %d", req.URL.Path, i)
+		}
+	}
+}